@@ -13,18 +13,19 @@ func init() {
 	nodes.RegisterCommand("says", Says)
 }
 
-func Honk([]string) error {
+func Honk(*nodes.Args) error {
 	fmt.Println("HONK! 🦢")
 	return nil
 }
 
-func Says(args []string) error {
-	if len(args) < 1 {
+func Says(args *nodes.Args) error {
+	message := args.List("message")
+	if len(message) < 1 {
 		return fmt.Errorf("swan needs something to say")
 	}
 
 	// join all arguments into a single message
-	say := strings.Join(args, " ") + " - 🦢"
+	say := strings.Join(message, " ") + " - 🦢"
 	fmt.Println(say)
 	return nil
 }
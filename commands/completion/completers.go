@@ -0,0 +1,49 @@
+// commands/completion/completers.go
+package completion
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/rAlexander89/swan/commands/project"
+	"github.com/rAlexander89/swan/manifest"
+	"github.com/rAlexander89/swan/nodes"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+func init() {
+	nodes.RegisterCompleter("domains", func(string) []string { return manifest.Domains() })
+	nodes.RegisterCompleter("operations", completeOperations)
+	nodes.RegisterCompleter("envs", completeEnvs)
+	nodes.RegisterCompleter("shells", func(string) []string { return Shells() })
+	nodes.RegisterCompleter("templates", func(string) []string { return project.TemplateNames() })
+}
+
+// completeOperations extends the letters typed so far with each CRUDI letter not yet used
+func completeOperations(prefix string) []string {
+	typed := strings.ToUpper(prefix)
+
+	var candidates []string
+	for _, op := range project.Ops {
+		if !strings.ContainsRune(typed, op) {
+			candidates = append(candidates, prefix+string(op))
+		}
+	}
+
+	return candidates
+}
+
+// completeEnvs lists the environments that have a file in configs/
+func completeEnvs(string) []string {
+	files, err := filepath.Glob(workspace.Path("configs", "*.json"))
+	if err != nil {
+		return nil
+	}
+
+	var envs []string
+	for _, file := range files {
+		envs = append(envs, strings.TrimSuffix(filepath.Base(file), ".json"))
+	}
+
+	return envs
+}
@@ -0,0 +1,130 @@
+// commands/completion/completion.go
+package completion
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rAlexander89/swan/nodes"
+	"github.com/rAlexander89/swan/plugins"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+func init() {
+	nodes.RegisterCommand("completion", Completion)
+	nodes.RegisterCommand("__complete", Complete)
+}
+
+// scripts hold the completion script of each shell. every script defers to
+// the hidden __complete command so candidates always come from the node tree
+var scripts = map[string]string{
+	"bash": `# bash completion for {{name}}
+# add to ~/.bashrc: source <({{name}} completion bash)
+_{{name}}_completions() {
+    local IFS=$'\n'
+    COMPREPLY=($({{name}} __complete -- "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _{{name}}_completions {{name}}
+`,
+	"zsh": `#compdef {{name}}
+# zsh completion for {{name}}
+# add to ~/.zshrc: source <({{name}} completion zsh)
+_{{name}}() {
+    local -a completions
+    completions=("${(@f)$({{name}} __complete -- "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    compadd -- ${completions:#}
+}
+
+if ! (( $+functions[compdef] )); then
+    autoload -Uz compinit && compinit
+fi
+compdef _{{name}} {{name}}
+`,
+	"fish": `# fish completion for {{name}}
+# add to ~/.config/fish/config.fish: {{name}} completion fish | source
+function __{{name}}_complete
+    set -l tokens (commandline -opc)
+    set -e tokens[1]
+    {{name}} __complete -- $tokens (commandline -ct) 2>/dev/null
+end
+complete -c {{name}} -f -a '(__{{name}}_complete)'
+`,
+}
+
+// Shells returns the shells a completion script can be printed for
+func Shells() []string {
+	return []string{"bash", "fish", "zsh"}
+}
+
+// Completion prints the completion script for the given shell
+func Completion(args *nodes.Args) error {
+	shell := args.String("shell")
+
+	script, exists := scripts[shell]
+	if !exists {
+		return fmt.Errorf("unsupported shell %s, expected one of: %s", shell, strings.Join(Shells(), ", "))
+	}
+
+	root, err := nodes.LoadNodes()
+	if err != nil {
+		return fmt.Errorf("error loading nodes: %v", err)
+	}
+
+	fmt.Print(strings.ReplaceAll(script, "{{name}}", root.Name))
+	return nil
+}
+
+// Complete prints one completion candidate per line for the words typed so far.
+// the last word is the one being completed and may be empty
+func Complete(args *nodes.Args) error {
+	candidates, err := candidates(args.List("words"))
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range candidates {
+		fmt.Println(candidate)
+	}
+
+	return nil
+}
+
+// candidates returns the completions of the last of words. global flags are
+// taken out of the words before them the way main does, and -C points the
+// completers at the project it names
+func candidates(words []string) ([]string, error) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := words[len(words)-1]
+
+	// e.g. -C with its directory still being typed, left to the shell
+	ctx, typed, err := workspace.ParseFlags(words[:len(words)-1])
+	if err != nil {
+		return nil, nil
+	}
+	if ctx.Dir != "" {
+		if err := workspace.SetDir(ctx.Dir); err != nil {
+			return nil, nil
+		}
+	}
+
+	root, err := plugins.LoadTree()
+	if err != nil {
+		return nil, fmt.Errorf("error loading nodes: %v", err)
+	}
+
+	candidates := root.Complete(append(typed, current))
+	if strings.HasPrefix(current, "-") {
+		for _, flag := range workspace.GlobalFlags {
+			if strings.HasPrefix(flag, current) && !slices.Contains(candidates, flag) {
+				candidates = append(candidates, flag)
+			}
+		}
+		sort.Strings(candidates)
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,95 @@
+// commands/completion/completion_test.go
+package completion
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/rAlexander89/swan/workspace"
+)
+
+// sampleProject makes a module at dir/svc with one domain and one env
+func sampleProject(t *testing.T, dir string) string {
+	t.Helper()
+
+	svc := filepath.Join(dir, "svc")
+	for _, sub := range []string{filepath.Join("internal", "core", "domains", "blog_post"), "configs"} {
+		if err := os.MkdirAll(filepath.Join(svc, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		"go.mod":            "module example.com/svc\n",
+		"configs/prod.json": "{}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(svc, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return svc
+}
+
+func TestCandidatesGlobalFlags(t *testing.T) {
+	dir := t.TempDir()
+	svc := sampleProject(t, dir)
+	t.Setenv("HOME", t.TempDir())
+
+	// -C is relative to the working directory, as in the shell
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	tests := []struct {
+		name  string
+		dir   string
+		words []string
+		want  string
+	}{
+		{"plain", svc, []string{"hatch", ""}, "BlogPost"},
+		{"verbose", svc, []string{"-v", "hatch", ""}, "BlogPost"},
+		{"dry run and quiet", svc, []string{"--dry-run", "-q", "hatch", ""}, "BlogPost"},
+		{"dir", "", []string{"-C", "svc", "hatch", ""}, "BlogPost"},
+		{"long dir", "", []string{"--dir", svc, "hatch", ""}, "BlogPost"},
+		{"env of dir", "", []string{"-C", "svc", "config", "check", ""}, "prod"},
+		{"global flag", svc, []string{"--dry"}, "--dry-run"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := workspace.Current()
+			workspace.Set(&workspace.Context{Dir: tt.dir})
+			t.Cleanup(func() { workspace.Set(previous) })
+
+			got, err := candidates(tt.words)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Contains(got, tt.want) {
+				t.Errorf("candidates(%q) = %q, want %q among them", tt.words, got, tt.want)
+			}
+		})
+	}
+}
+
+// the directory after -C is left to the shell
+func TestCandidatesDirValue(t *testing.T) {
+	previous := workspace.Current()
+	workspace.Set(&workspace.Context{Dir: t.TempDir()})
+	t.Cleanup(func() { workspace.Set(previous) })
+
+	got, err := candidates([]string{"-C", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("candidates after -C = %q, want none", got)
+	}
+}
@@ -0,0 +1,373 @@
+// commands/config/add.go
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rAlexander89/swan/commands/project"
+	"github.com/rAlexander89/swan/genfile"
+	"github.com/rAlexander89/swan/nodes"
+	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+func init() {
+	nodes.RegisterCommand("config add", Add)
+}
+
+// fieldTypes are the types swan config add accepts, with their zero value in json
+var fieldTypes = map[string]string{
+	"string":   `""`,
+	"int":      `0`,
+	"int64":    `0`,
+	"float64":  `0`,
+	"bool":     `false`,
+	"[]string": `[]`,
+}
+
+// initialisms are spelled in capitals in go field names, e.g. redis_url -> RedisURL
+var initialisms = map[string]bool{
+	"api": true, "db": true, "dsn": true, "http": true, "id": true, "ip": true,
+	"sql": true, "tls": true, "ttl": true, "uri": true, "url": true,
+}
+
+// keyPattern is a segment of a config path, i.e. a json key
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// newField is a field to add, parsed from path:type=default
+type newField struct {
+	path       []string
+	goType     string
+	def        string
+	hasDefault bool
+	value      json.RawMessage // the default, or the zero value, as written to the config files
+}
+
+// Add adds fields to the generated Config struct and their defaults to every
+// configs/*.json. values already in a config file are kept
+func Add(args *nodes.Args) error {
+	var fields []newField
+	for _, spec := range args.List("fields") {
+		f, err := parseField(spec)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("expected at least one field, e.g. redis.addr:string=localhost:6379")
+	}
+
+	// every spec is checked before a file is written
+	configPath, err := findConfigFile()
+	if err != nil {
+		return err
+	}
+	if err := addStructFields(configPath, fields); err != nil {
+		return err
+	}
+
+	envFiles, err := EnvFiles()
+	if err != nil {
+		return fmt.Errorf("failed to list config files: %v", err)
+	}
+	for _, path := range envFiles {
+		if err := mergeDefaults(path, fields); err != nil {
+			return err
+		}
+	}
+
+	// .env.example lists an override per field
+	return project.WriteEnvExample(workspace.Root())
+}
+
+// parseField parses path:type=default, e.g. redis.db:int=0. the default is optional
+func parseField(spec string) (newField, error) {
+	path, rest, found := strings.Cut(spec, ":")
+	if !found {
+		return newField{}, fmt.Errorf("invalid field %s, expected path:type=default", spec)
+	}
+
+	f := newField{path: strings.Split(path, ".")}
+	f.goType, f.def, f.hasDefault = strings.Cut(rest, "=")
+
+	for _, key := range f.path {
+		if !keyPattern.MatchString(key) {
+			return newField{}, fmt.Errorf("invalid field %s: %q is not a snake_case key", spec, key)
+		}
+	}
+
+	zero, supported := fieldTypes[f.goType]
+	if !supported {
+		return newField{}, fmt.Errorf("invalid field %s: unsupported type %s, expected one of: string, int, int64, float64, bool, []string", spec, f.goType)
+	}
+
+	f.value = json.RawMessage(zero)
+	if f.hasDefault {
+		value, err := defaultJSON(f.goType, f.def)
+		if err != nil {
+			return newField{}, fmt.Errorf("invalid field %s: %v", spec, err)
+		}
+		f.value = value
+	}
+
+	return f, nil
+}
+
+// defaultJSON returns def as a json value of goType
+func defaultJSON(goType, def string) (json.RawMessage, error) {
+	var v any
+	var err error
+
+	switch goType {
+	case "string":
+		v = def
+	case "int", "int64":
+		v, err = strconv.ParseInt(def, 10, 64)
+	case "float64":
+		v, err = strconv.ParseFloat(def, 64)
+	case "bool":
+		v, err = strconv.ParseBool(def)
+	case "[]string":
+		v = []string{}
+		if def != "" {
+			v = strings.Split(def, ",")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("default %q is not a valid %s", def, goType)
+	}
+
+	return json.Marshal(v)
+}
+
+// goName returns the field name of a json key, e.g. redis_url -> RedisURL
+func goName(key string) string {
+	var name strings.Builder
+	for _, word := range strings.Split(key, "_") {
+		if initialisms[word] {
+			name.WriteString(strings.ToUpper(word))
+		} else {
+			name.WriteString(utils.ToUpperFirst(word))
+		}
+	}
+	return name.String()
+}
+
+// findConfigFile returns the file of the config package declaring Config
+func findConfigFile() (string, error) {
+	paths, err := filepath.Glob(filepath.Join(ConfigDir(), "*.go"))
+	if err != nil {
+		return "", err
+	}
+
+	for _, path := range paths {
+		src, err := workspace.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %v", workspace.Rel(path), err)
+		}
+		if bytes.Contains(src, []byte("type Config struct")) {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("no Config struct in %s, is this a swan project?", workspace.Rel(ConfigDir()))
+}
+
+// addStructFields adds fields to the Config struct in the file at path, creating
+// the nested structs of their sections. fields that exist are left alone
+func addStructFields(path string, fields []newField) error {
+	data, err := workspace.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", workspace.Rel(path), err)
+	}
+
+	// a file swan still owns is stamped again, an edited one keeps its header
+	src, generator := data, ""
+	if header, body, ok := genfile.Parse(path, data); ok && genfile.Hash(body) == header.Hash {
+		src, generator = body, header.Generator
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %v", workspace.Rel(path), err)
+	}
+
+	root := findStruct(file, "Config")
+	if root == nil {
+		return fmt.Errorf("no Config struct in %s", workspace.Rel(path))
+	}
+
+	added := 0
+	for _, f := range fields {
+		ok, err := addField(root, f)
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %v", strings.Join(f.path, "."), err)
+		}
+		if ok {
+			added++
+			workspace.Verbosef("added %s to Config\n", strings.Join(f.path, "."))
+		} else {
+			workspace.Printf("%s is already in Config, left alone\n", strings.Join(f.path, "."))
+		}
+	}
+	if added == 0 {
+		return nil
+	}
+
+	var out bytes.Buffer
+	if err := format.Node(&out, fset, file); err != nil {
+		return fmt.Errorf("failed to print %s: %v", workspace.Rel(path), err)
+	}
+
+	if generator != "" {
+		err = genfile.WriteFile(generator, path, out.Bytes(), 0644)
+	} else {
+		err = workspace.WriteFile(path, out.Bytes(), 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", workspace.Rel(path), err)
+	}
+
+	workspace.Printf("added %d field(s) to Config in %s\n", added, workspace.Rel(path))
+	return nil
+}
+
+// findStruct returns the struct type called name declared in file
+func findStruct(file *ast.File, name string) *ast.StructType {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if ts := spec.(*ast.TypeSpec); ts.Name.Name == name {
+				st, _ := ts.Type.(*ast.StructType)
+				return st
+			}
+		}
+	}
+	return nil
+}
+
+// addField walks st down f's path, adding the sections it lacks, and adds
+// the field at the end. it reports false when the field exists already
+func addField(st *ast.StructType, f newField) (bool, error) {
+	for i, key := range f.path {
+		last := i == len(f.path)-1
+		existing := fieldByKey(st, key)
+
+		if existing != nil {
+			if last {
+				return false, nil
+			}
+
+			nested, ok := existing.Type.(*ast.StructType)
+			if !ok {
+				return false, fmt.Errorf("%s is not an inline struct", strings.Join(f.path[:i+1], "."))
+			}
+			st = nested
+			continue
+		}
+
+		tag := fmt.Sprintf("json:%q", key)
+		var typ ast.Expr
+		if last {
+			typ = ast.NewIdent(f.goType)
+			if f.hasDefault && f.goType != "[]string" {
+				tag += fmt.Sprintf(" default:%q", f.def)
+			}
+		} else {
+			typ = &ast.StructType{Fields: &ast.FieldList{}}
+		}
+
+		field := &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(goName(key))},
+			Type:  typ,
+			Tag:   &ast.BasicLit{Kind: token.STRING, Value: "`" + tag + "`"},
+		}
+		st.Fields.List = append(st.Fields.List, field)
+
+		if last {
+			return true, nil
+		}
+		st = typ.(*ast.StructType)
+	}
+
+	return false, nil
+}
+
+// fieldByKey returns the field of st whose json name is key
+func fieldByKey(st *ast.StructType, key string) *ast.Field {
+	for _, field := range st.Fields.List {
+		for _, ident := range field.Names {
+			name := ident.Name
+			if field.Tag != nil {
+				if tag, err := strconv.Unquote(field.Tag.Value); err == nil {
+					if jsonName, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ","); jsonName != "" {
+						name = jsonName
+					}
+				}
+			}
+			if name == key {
+				return field
+			}
+		}
+	}
+	return nil
+}
+
+// mergeDefaults adds the defaults of fields to the config file at path,
+// keeping every value it already has
+func mergeDefaults(path string, fields []newField) error {
+	data, err := workspace.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", workspace.Rel(path), err)
+	}
+
+	obj := newObject()
+	if len(bytes.TrimSpace(data)) > 0 {
+		if obj, err = parseObject(data); err != nil {
+			return fmt.Errorf("failed to parse %s: %v", workspace.Rel(path), err)
+		}
+	}
+
+	added := 0
+	for _, f := range fields {
+		ok, err := obj.merge(f.path, f.value)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to %s: %v", strings.Join(f.path, "."), workspace.Rel(path), err)
+		}
+		if ok {
+			added++
+		}
+	}
+	if added == 0 {
+		workspace.Verbosef("%s has every field already\n", workspace.Rel(path))
+		return nil
+	}
+
+	out, err := obj.encode(detectIndent(data))
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %v", workspace.Rel(path), err)
+	}
+
+	if err := workspace.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", workspace.Rel(path), err)
+	}
+
+	workspace.Printf("added %d default(s) to %s\n", added, workspace.Rel(path))
+	return nil
+}
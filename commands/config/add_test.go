@@ -0,0 +1,114 @@
+// commands/config/add_test.go
+package config
+
+import (
+	"bytes"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+// configSource is a Config struct like the generated one, with a section, a
+// field in it and a field that is not a struct
+const configSource = `package config
+
+type Config struct {
+	Server struct {
+		Host string ` + "`json:\"host\"`" + `
+	} ` + "`json:\"server\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+`
+
+func TestAddField(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		added   bool
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "new nested section",
+			spec:  "redis.pool.size:int=10",
+			added: true,
+			want: `package config
+
+type Config struct {
+	Server struct {
+		Host string ` + "`json:\"host\"`" + `
+	} ` + "`json:\"server\"`" + `
+	Name  string ` + "`json:\"name\"`" + `
+	Redis struct {
+		Pool struct {
+			Size int ` + "`json:\"size\" default:\"10\"`" + `
+		} ` + "`json:\"pool\"`" + `
+	} ` + "`json:\"redis\"`" + `
+}
+`,
+		},
+		{
+			name:  "existing section",
+			spec:  "server.api_url:string",
+			added: true,
+			want: `package config
+
+type Config struct {
+	Server struct {
+		Host   string ` + "`json:\"host\"`" + `
+		APIURL string ` + "`json:\"api_url\"`" + `
+	} ` + "`json:\"server\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+`,
+		},
+		{
+			name: "existing field",
+			spec: "server.host:string=0.0.0.0",
+			want: configSource,
+		},
+		{
+			name:    "field where a value is",
+			spec:    "name.first:string",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parseField(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "config.go", configSource, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			added, err := addField(findStruct(file, "Config"), f)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("addField(%s) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if added != tt.added {
+				t.Errorf("addField(%s) = %v, want %v", tt.spec, added, tt.added)
+			}
+
+			var got bytes.Buffer
+			if err := format.Node(&got, fset, file); err != nil {
+				t.Fatal(err)
+			}
+			want, err := format.Source([]byte(tt.want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != string(want) {
+				t.Errorf("addField(%s) gives\n%s\nwant\n%s", tt.spec, got.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+// commands/config/check.go
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rAlexander89/swan/commands/project"
+	"github.com/rAlexander89/swan/nodes"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+func init() {
+	nodes.RegisterCommand("config check", Check)
+}
+
+// Check validates configs/<env>.json, or every config file, against the
+// defaults and validate tags of the generated Config. it applies the rules the
+// generated Validate does, without building the project. the overrides set in
+// swan's own environment are only applied with --with-env, so the result
+// doesn't depend on the shell it runs in
+func Check(args *nodes.Args) error {
+	fields, err := LoadSchema()
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	if env := args.String("env"); env != "" {
+		paths = []string{workspace.Path("configs", env+".json")}
+	} else if paths, err = EnvFiles(); err != nil {
+		return fmt.Errorf("failed to list config files: %v", err)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no config files in %s", workspace.Rel(workspace.Path("configs")))
+	}
+
+	withEnv := args.Bool("with-env")
+
+	invalid := 0
+	for _, path := range paths {
+		problems, err := checkFile(path, fields, withEnv)
+		if err != nil {
+			return err
+		}
+
+		if len(problems) == 0 {
+			workspace.Printf("%s ok\n", workspace.Rel(path))
+			continue
+		}
+
+		invalid++
+		fmt.Printf("%s:\n", workspace.Rel(path))
+		for _, problem := range problems {
+			fmt.Printf("  %s\n", problem)
+		}
+	}
+
+	if !withEnv {
+		reportOverrides(fields)
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d config file(s) invalid", invalid, len(paths))
+	}
+	return nil
+}
+
+// reportOverrides lists the overrides set in swan's environment, which the
+// check left out
+func reportOverrides(fields []Field) {
+	var set []string
+	for _, field := range fields {
+		if _, exists := os.LookupEnv(envName(field.Path)); exists {
+			set = append(set, envName(field.Path))
+		}
+	}
+	if len(set) == 0 {
+		return
+	}
+
+	workspace.Printf("not applied, pass --with-env to check the files with them: %s\n", strings.Join(set, ", "))
+}
+
+// checkFile returns every problem of the config file at path, with the
+// environment's overrides applied when withEnv is set
+func checkFile(path string, fields []Field, withEnv bool) ([]string, error) {
+	data, err := workspace.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", workspace.Rel(path), err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc map[string]any
+	if err := decoder.Decode(&doc); err != nil {
+		return []string{fmt.Sprintf("invalid json: %v", err)}, nil
+	}
+
+	var problems []string
+	for _, field := range fields {
+		v, err := resolve(field, doc, withEnv)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", field.Path, err))
+			continue
+		}
+
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			if rule == "" {
+				continue
+			}
+			if err := checkRule(rule, v); err != nil {
+				// required fields can be left to the environment
+				if rule == "required" {
+					err = fmt.Errorf("%v, set it in %s or %s", err, filepath.Base(path), envName(field.Path))
+				}
+				problems = append(problems, fmt.Sprintf("%s: %v", field.Path, err))
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+// value is a config value as the generated loader would see it
+type value struct {
+	kind string // string, number or bool
+	str  string
+	num  float64
+	zero bool
+}
+
+// resolve returns the value of field the loader ends up with: the environment
+// variable when withEnv is set, else the file's value, else the default
+func resolve(field Field, doc map[string]any, withEnv bool) (value, error) {
+	if raw, set := os.LookupEnv(envName(field.Path)); set && withEnv {
+		return parse(field.Type, raw)
+	}
+
+	var node any = doc
+	for _, key := range strings.Split(field.Path, ".") {
+		section, ok := node.(map[string]any)
+		if !ok {
+			node = nil
+			break
+		}
+		node = section[key]
+	}
+
+	switch v := node.(type) {
+	case nil:
+		def, _ := field.Tag.Lookup("default")
+		return parse(field.Type, def)
+	case string:
+		if field.Type != "string" {
+			return value{}, fmt.Errorf("expected %s, got a string", field.Type)
+		}
+		return value{kind: "string", str: v, zero: v == ""}, nil
+	case bool:
+		if field.Type != "bool" {
+			return value{}, fmt.Errorf("expected %s, got a bool", field.Type)
+		}
+		return value{kind: "bool", zero: !v}, nil
+	case json.Number:
+		if !isNumber(field.Type) {
+			return value{}, fmt.Errorf("expected %s, got a number", field.Type)
+		}
+		if isInteger(field.Type) && strings.ContainsAny(v.String(), ".eE") {
+			return value{}, fmt.Errorf("expected %s, got %s", field.Type, v)
+		}
+		n, err := v.Float64()
+		if err != nil {
+			return value{}, err
+		}
+		return value{kind: "number", num: n, zero: n == 0}, nil
+	default:
+		// lists and maps are not checked
+		return value{kind: "other"}, nil
+	}
+}
+
+// parse reads a default or an environment variable like the generated setField
+func parse(goType, raw string) (value, error) {
+	switch {
+	case goType == "string":
+		return value{kind: "string", str: raw, zero: raw == ""}, nil
+	case raw == "":
+		return value{zero: true}, nil
+	case goType == "bool":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return value{}, err
+		}
+		return value{kind: "bool", zero: !b}, nil
+	case goType == "time.Duration":
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return value{}, err
+		}
+		return value{kind: "number", num: float64(d), zero: d == 0}, nil
+	case isNumber(goType):
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil || (isInteger(goType) && strings.ContainsAny(raw, ".eE")) {
+			return value{}, fmt.Errorf("invalid %s %q", goType, raw)
+		}
+		return value{kind: "number", num: n, zero: n == 0}, nil
+	}
+
+	return value{}, nil
+}
+
+// checkRule applies one rule of a validate tag to v, with the messages of the
+// generated Validate
+func checkRule(rule string, v value) error {
+	name, arg, _ := strings.Cut(rule, "=")
+
+	switch name {
+	case "required":
+		if v.zero {
+			return fmt.Errorf("is required")
+		}
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("invalid rule %s", rule)
+		}
+
+		n, unit := v.num, ""
+		switch v.kind {
+		case "string":
+			n, unit = float64(len(v.str)), " characters"
+		case "bool":
+			return fmt.Errorf("rule %s does not apply to bool", name)
+		case "other":
+			return nil
+		}
+		if name == "min" && n < limit {
+			return fmt.Errorf("must be at least %s%s", arg, unit)
+		}
+		if name == "max" && n > limit {
+			return fmt.Errorf("must be at most %s%s", arg, unit)
+		}
+	case "url":
+		if v.kind != "string" {
+			return fmt.Errorf("rule url does not apply to %s", v.kind)
+		}
+		// an empty value is left to required
+		if u, err := url.Parse(v.str); v.str != "" && (err != nil || u.Scheme == "" || u.Host == "") {
+			return fmt.Errorf("must be a url like scheme://host/path")
+		}
+	default:
+		return fmt.Errorf("unknown rule %s", rule)
+	}
+
+	return nil
+}
+
+// envName is the generated EnvName: the environment variable overriding the field at path
+func envName(path string) string {
+	return project.EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+}
+
+func isNumber(goType string) bool {
+	return isInteger(goType) || goType == "float32" || goType == "float64"
+}
+
+func isInteger(goType string) bool {
+	switch goType {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "time.Duration":
+		return true
+	}
+	return false
+}
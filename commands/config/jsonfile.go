@@ -0,0 +1,143 @@
+// commands/config/jsonfile.go
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// object is a json object that keeps the order of its keys, so a config file
+// rewritten by swan config add only differs by what was added
+type object struct {
+	keys   []string
+	values map[string]any // *object for nested objects, json.RawMessage otherwise
+}
+
+func newObject() *object {
+	return &object{values: make(map[string]any)}
+}
+
+// parseObject reads a json object. values other than objects are kept as written
+func parseObject(data []byte) (*object, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a json object")
+	}
+
+	obj := newObject()
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+			nested, err := parseObject(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", key, err)
+			}
+			obj.set(key, nested)
+		} else {
+			obj.set(key, raw)
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (o *object) set(key string, v any) {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = v
+}
+
+// merge sets the value at path unless the file already has one. it reports
+// whether the value was added
+func (o *object) merge(path []string, v json.RawMessage) (bool, error) {
+	key := path[0]
+	current, exists := o.values[key]
+
+	if len(path) == 1 {
+		if exists {
+			return false, nil
+		}
+		o.set(key, v)
+		return true, nil
+	}
+
+	if !exists {
+		current = newObject()
+		o.set(key, current)
+	}
+
+	nested, ok := current.(*object)
+	if !ok {
+		return false, fmt.Errorf("%s is not an object", key)
+	}
+	return nested.merge(path[1:], v)
+}
+
+// encode writes the object indented by indent per level, like json.MarshalIndent
+func (o *object) encode(indent string) ([]byte, error) {
+	var b bytes.Buffer
+	if err := o.write(&b, "", indent); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (o *object) write(b *bytes.Buffer, prefix, indent string) error {
+	if len(o.keys) == 0 {
+		b.WriteString("{}")
+		return nil
+	}
+
+	b.WriteString("{\n")
+	for i, key := range o.keys {
+		name, _ := json.Marshal(key)
+		fmt.Fprintf(b, "%s%s%s: ", prefix, indent, name)
+
+		switch v := o.values[key].(type) {
+		case *object:
+			if err := v.write(b, prefix+indent, indent); err != nil {
+				return err
+			}
+		case json.RawMessage:
+			var value bytes.Buffer
+			if err := json.Indent(&value, v, prefix+indent, indent); err != nil {
+				return fmt.Errorf("%s: %v", key, err)
+			}
+			b.Write(value.Bytes())
+		}
+
+		if i < len(o.keys)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(prefix + "}")
+
+	return nil
+}
+
+// detectIndent returns the indentation of the first indented line of data
+func detectIndent(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		if trimmed := strings.TrimLeft(line, " \t"); trimmed != line && trimmed != "" {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "    "
+}
@@ -0,0 +1,93 @@
+// commands/config/jsonfile_test.go
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		path    []string
+		value   string
+		added   bool
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "new nested section",
+			data:  "{\n    \"server\": {\n        \"port\": 8080\n    }\n}",
+			path:  []string{"redis", "pool", "size"},
+			value: "10",
+			added: true,
+			want:  "{\n    \"server\": {\n        \"port\": 8080\n    },\n    \"redis\": {\n        \"pool\": {\n            \"size\": 10\n        }\n    }\n}",
+		},
+		{
+			name:  "existing section",
+			data:  "{\n    \"server\": {\n        \"port\": 8080\n    }\n}",
+			path:  []string{"server", "host"},
+			value: `"localhost"`,
+			added: true,
+			want:  "{\n    \"server\": {\n        \"port\": 8080,\n        \"host\": \"localhost\"\n    }\n}",
+		},
+		{
+			name:  "existing value kept",
+			data:  "{\n    \"server\": {\n        \"port\": 9090\n    }\n}",
+			path:  []string{"server", "port"},
+			value: "8080",
+			want:  "{\n    \"server\": {\n        \"port\": 9090\n    }\n}",
+		},
+		{
+			name:  "key order and indentation kept",
+			data:  "{\n  \"zeta\": [\n    \"a\",\n    \"b\"\n  ],\n  \"alpha\": {\n    \"on\": true\n  },\n  \"mid\": \"x\"\n}",
+			path:  []string{"alpha", "level"},
+			value: "2",
+			added: true,
+			want:  "{\n  \"zeta\": [\n    \"a\",\n    \"b\"\n  ],\n  \"alpha\": {\n    \"on\": true,\n    \"level\": 2\n  },\n  \"mid\": \"x\"\n}",
+		},
+		{
+			name:  "tabs kept",
+			data:  "{\n\t\"name\": \"svc\"\n}",
+			path:  []string{"debug"},
+			value: "false",
+			added: true,
+			want:  "{\n\t\"name\": \"svc\",\n\t\"debug\": false\n}",
+		},
+		{
+			name:    "section where a value is",
+			data:    "{\n    \"name\": \"svc\"\n}",
+			path:    []string{"name", "first"},
+			value:   `""`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, err := parseObject([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			added, err := obj.merge(tt.path, json.RawMessage(tt.value))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("merge(%v) error = %v, want error %v", tt.path, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if added != tt.added {
+				t.Errorf("merge(%v) = %v, want %v", tt.path, added, tt.added)
+			}
+
+			got, err := obj.encode(detectIndent([]byte(tt.data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("merge(%v) gives\n%s\nwant\n%s", tt.path, got, tt.want)
+			}
+		})
+	}
+}
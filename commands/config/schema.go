@@ -0,0 +1,152 @@
+// commands/config/schema.go
+package config
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rAlexander89/swan/workspace"
+)
+
+// Field is a config field holding a value, as declared in the generated Config struct
+type Field struct {
+	Path string // json path, e.g. db.postgres.uri
+	Type string // go type, e.g. string or int
+	Tag  reflect.StructTag
+}
+
+// ConfigDir returns the generated config package of the project
+func ConfigDir() string {
+	return workspace.Path("internal", "infrastructure", "config")
+}
+
+// LoadSchema reads the fields of Config from the config package source.
+// struct types declared elsewhere in the package are followed
+func LoadSchema() ([]Field, error) {
+	dir := ConfigDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config package: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	types := make(map[string]ast.Expr)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		src, err := workspace.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", entry.Name(), err)
+		}
+
+		file, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", entry.Name(), err)
+		}
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				types[ts.Name.Name] = ts.Type
+			}
+		}
+	}
+
+	root, ok := types["Config"].(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("no Config struct in %s", workspace.Rel(dir))
+	}
+
+	var fields []Field
+	collectFields(root, "", types, &fields)
+	return fields, nil
+}
+
+// collectFields appends the value fields of st, descending into nested structs
+func collectFields(st *ast.StructType, prefix string, types map[string]ast.Expr, fields *[]Field) {
+	for _, f := range st.Fields.List {
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			if unquoted, err := strconv.Unquote(f.Tag.Value); err == nil {
+				tag = reflect.StructTag(unquoted)
+			}
+		}
+
+		for _, ident := range f.Names {
+			if !ident.IsExported() {
+				continue
+			}
+
+			name, _, _ := strings.Cut(tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = ident.Name
+			}
+			path := strings.TrimPrefix(prefix+"."+name, ".")
+
+			if nested, ok := structOf(f.Type, types); ok {
+				collectFields(nested, path, types, fields)
+				continue
+			}
+
+			*fields = append(*fields, Field{Path: path, Type: typeString(f.Type), Tag: tag})
+		}
+	}
+}
+
+// structOf returns the struct expr is, inline or declared in the package
+func structOf(expr ast.Expr, types map[string]ast.Expr) (*ast.StructType, bool) {
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return t, true
+	case *ast.Ident:
+		if declared, ok := types[t.Name]; ok {
+			return structOf(declared, types)
+		}
+	}
+	return nil, false
+}
+
+// typeString renders a field type, e.g. []string or time.Duration
+func typeString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return typeString(t.X) + "." + t.Sel.Name
+	case *ast.ArrayType:
+		return "[]" + typeString(t.Elt)
+	case *ast.StarExpr:
+		return "*" + typeString(t.X)
+	case *ast.MapType:
+		return "map[" + typeString(t.Key) + "]" + typeString(t.Value)
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+// EnvFiles returns the config files of the project, one per environment, sorted
+func EnvFiles() ([]string, error) {
+	paths, err := filepath.Glob(workspace.Path("configs", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
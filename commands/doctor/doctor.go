@@ -0,0 +1,206 @@
+// commands/doctor/doctor.go
+package doctor
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rAlexander89/swan/commands/project"
+	"github.com/rAlexander89/swan/genfile"
+	"github.com/rAlexander89/swan/manifest"
+	"github.com/rAlexander89/swan/nodes"
+	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+func init() {
+	nodes.RegisterCommand("doctor", Doctor)
+}
+
+// Doctor checks the project in the current directory, or swan itself with --self
+func Doctor(args *nodes.Args) error {
+	if args.Bool("self") {
+		return checkSelf()
+	}
+
+	return checkProject()
+}
+
+// checkSelf cross checks the embedded nodes.json against the registered commands
+func checkSelf() error {
+	root, err := nodes.LoadNodes()
+	if err != nil {
+		return fmt.Errorf("error loading nodes: %v", err)
+	}
+
+	problems := nodes.Check(root)
+	for _, p := range problems {
+		fmt.Printf("✗ %s\n", p)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s) between nodes.json and the command registry", len(problems))
+	}
+
+	fmt.Println("✓ nodes.json matches the command registry")
+	return nil
+}
+
+// checkProject verifies the current directory looks like a swan project
+func checkProject() error {
+	projectName, err := utils.GetProjectName()
+	if err != nil {
+		return fmt.Errorf("not a swan project: %v", err)
+	}
+
+	fmt.Printf("✓ module %s\n", projectName)
+
+	m, err := manifest.LoadOptional()
+	if err != nil {
+		return err
+	}
+
+	problems := 0
+	if m == nil {
+		fmt.Printf("✗ no %s, layout checked against the default template\n", manifest.FileName)
+	} else {
+		fmt.Printf("✓ %s: template %s (swan %s), database %s\n", manifest.FileName, m.Template.Name, m.Template.Version, m.Database)
+		if m.Module != projectName {
+			fmt.Printf("✗ %s records module %s but go.mod declares %s\n", manifest.FileName, m.Module, projectName)
+			problems++
+		}
+	}
+
+	for _, dir := range expectedDirs(m) {
+		if _, err := os.Stat(workspace.Path(dir)); err != nil {
+			fmt.Printf("✗ missing %s\n", dir)
+			problems++
+			continue
+		}
+
+		fmt.Printf("✓ %s\n", dir)
+	}
+
+	if m != nil {
+		problems += checkDomains(m)
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("found %d problem(s) in the project", problems)
+	}
+
+	return checkGenerated()
+}
+
+// expectedDirs returns the directories every project of the manifest's template has
+func expectedDirs(m *manifest.Manifest) []string {
+	dirs := []string{
+		filepath.Join("internal", "core", "domains"),
+		filepath.Join("internal", "infrastructure", "config"),
+		"configs",
+	}
+	if m == nil {
+		return dirs
+	}
+
+	tmpl, err := project.LoadTemplate(m.Template.Name)
+	if err != nil {
+		workspace.Verbosef("%v, layout checked against the default template\n", err)
+		return dirs
+	}
+
+	var expected []string
+	for _, dir := range dirs {
+		if tmpl.HasDir(dir) {
+			expected = append(expected, dir)
+		}
+	}
+	return expected
+}
+
+// checkDomains reports the layers recorded in swan.json that are missing on disk
+func checkDomains(m *manifest.Manifest) int {
+	problems := 0
+	for _, name := range m.DomainNames() {
+		d := m.Domains[name]
+
+		missing := 0
+		for _, layer := range d.Layers {
+			path, known := layerPath(m, name, layer)
+			if !known || workspace.Exists(workspace.Path(path)) {
+				continue
+			}
+			fmt.Printf("✗ %s: %s layer missing at %s\n", name, layer, path)
+			missing++
+		}
+
+		if missing == 0 {
+			ops := ""
+			if d.Ops != "" {
+				ops = " [" + d.Ops + "]"
+			}
+			fmt.Printf("✓ %s: %s%s\n", name, strings.Join(d.Layers, ", "), ops)
+		}
+		problems += missing
+	}
+	return problems
+}
+
+// layerPath returns where the generator of layer writes it for domain
+func layerPath(m *manifest.Manifest, domain, layer string) (string, bool) {
+	snake := utils.PascalToSnake(domain)
+
+	switch layer {
+	case manifest.LayerDomain:
+		return filepath.Join("internal", "core", "domains", snake), true
+	case manifest.LayerRepository:
+		return filepath.Join("internal", "app", "repositories", m.Database, "domains", snake), true
+	case manifest.LayerPort:
+		return filepath.Join("internal", "core", "ports", "repository", snake+"_repository.go"), true
+	case manifest.LayerService:
+		return filepath.Join("internal", "core", "services", snake+"_service"), true
+	case manifest.LayerHandler:
+		return filepath.Join("internal", "infrastructure", "http", "handlers", snake), true
+	case manifest.LayerRoutes:
+		return filepath.Join("internal", "infrastructure", "server", "routes", snake), true
+	}
+	return "", false
+}
+
+// checkGenerated reports the generated files that were edited since swan wrote them
+func checkGenerated() error {
+	generated, edited := 0, 0
+
+	err := filepath.WalkDir(workspace.Root(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") && path != workspace.Root() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		isEdited, isGenerated, err := genfile.Edited(path)
+		if err != nil || !isGenerated {
+			return err
+		}
+
+		generated++
+		if isEdited {
+			edited++
+			workspace.Verbosef("  edited %s\n", workspace.Rel(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan generated files: %v", err)
+	}
+
+	fmt.Printf("✓ %d generated file(s), %d edited since generation\n", generated, edited)
+	return nil
+}
@@ -3,16 +3,63 @@ package domain
 import (
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/rAlexander89/swan/commands/project"
+	"github.com/rAlexander89/swan/genfile"
+	"github.com/rAlexander89/swan/manifest"
 	"github.com/rAlexander89/swan/nodes"
 	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/wizard"
+	"github.com/rAlexander89/swan/workspace"
 )
 
 func init() {
 	nodes.RegisterCommand("domain", Create)
+	wizard.RegisterStep("domain", askFields)
+}
+
+// fieldTypes are offered when the wizard asks for a field's type
+var fieldTypes = []string{"string", "int", "int64", "float64", "bool", "time.Time"}
+
+// askFields asks for the domain's fields one at a time, then for the struct tags
+func askFields(p *wizard.Prompter, args *nodes.Args) ([]string, error) {
+	var tokens []string
+
+	if !args.Has("fields") {
+		var fields []string
+		for {
+			name, err := p.AskOptional("field name (empty to finish)")
+			if err != nil {
+				return nil, err
+			}
+			if name == "" {
+				break
+			}
+
+			dataType, err := p.Ask(fmt.Sprintf("type of %s (%s)", name, strings.Join(fieldTypes, ", ")), "string")
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, utils.ToUpperFirst(name), dataType)
+		}
+
+		if len(fields) > 0 {
+			tokens = append(append(tokens, "-f"), fields...)
+		}
+	}
+
+	if !args.Has("tags") && len(tokens) > 0 {
+		tags, err := p.Ask("struct tags", "json db")
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(append(tokens, "-t"), strings.Fields(strings.ReplaceAll(tags, ",", " "))...)
+	}
+
+	return tokens, nil
 }
 
 type domainArgs struct {
@@ -21,56 +68,53 @@ type domainArgs struct {
 	required bool
 }
 
-func Create(args []string) error {
-	argL := len(args)
-	if argL == 0 {
+func Create(args *nodes.Args) error {
+	domain := args.String("domain") // SomeDomain
+	if domain == "" {
 		return errors.New("expected at least 1 argument: domain name")
 	}
 
-	domain := args[0] // SomeDomain
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %v", err)
-	}
+	var err error
 	fileName := utils.PascalToSnake(domain) // Some_Domain
 	fileName = strings.ToLower(fileName)    // some_domain
-	domainPath := filepath.Join(currentDir, "internal", "core", "domains", fileName)
+	domainPath := workspace.Path("internal", "core", "domains", fileName)
 
-	fmt.Printf("generating new domain %s", domain)
+	workspace.Printf("generating new domain %s\n", domain)
 
 	// validate struct fields
 	var fields []utils.Field
 	var tags []string
 
-	// start @ 1. index 0 is the domain name
-	for i := 1; i < len(args); i++ {
-		arg := args[i]
-		var err error
+	if args.Has("fields") {
+		workspace.Verbosef("generating struct fields\n")
+		fields, err = utils.ParseArgFields(args.List("fields"), 0)
+		if err != nil {
+			return fmt.Errorf("failed to parse fields: %v ", err)
+		}
+	}
 
-		switch arg {
-		case "-f":
-			fmt.Println("generating struct fields")
-			fields, err = utils.ParseArgFields(args, i+1)
-			if err != nil {
-				return fmt.Errorf("failed to parse fields: %v ", err)
-			}
-		case "-t":
-			fmt.Println("generating struct field tags")
-			tags, err = utils.ParseArgTags(args, i+1)
-			if err != nil {
-				return fmt.Errorf("failed to parse tags: %v", err)
-			}
+	if args.Has("tags") {
+		workspace.Verbosef("generating struct field tags\n")
+		tags, err = utils.ParseArgTags(args.List("tags"), 0)
+		if err != nil {
+			return fmt.Errorf("failed to parse tags: %v", err)
 		}
 	}
 
-	if err := os.MkdirAll(domainPath, 0755); err != nil {
+	if err := workspace.MkdirAll(domainPath, 0755); err != nil {
 		return fmt.Errorf("failed to create domain directory: %v", err)
 	}
 
 	structFields := ""
+	imports := ""
 	for _, f := range fields {
 		tagStr := utils.GenerateTags(f.Name, tags)
 		structFields += fmt.Sprintf("    %s %s `%s`\n", f.Name, f.DataType, tagStr)
+
+		// time.Time fields need the time package
+		if strings.Contains(f.DataType, "time.") {
+			imports = "\n  import \"time\"\n"
+		}
 	}
 
 	// gen struct
@@ -78,24 +122,36 @@ func Create(args []string) error {
 	domainContent := fmt.Sprintf(
 		`// %s.go
   package %s
-
+%s
   type %s struct {
   %s
   }
   `,
 		fileName,                // domain_name.go
 		strings.ToLower(domain), // pacakge domainname
+		imports,                 // import "time"
 		domain,                  // type PublicDomain struct
 		structFields,            // struct fields
 	)
 
 	// write domain file
 	domainFile := filepath.Join(domainPath, fileName+".go")
-	if err := os.WriteFile(domainFile, []byte(domainContent), 0644); err != nil {
+	if err := genfile.WriteFile("domain", domainFile, []byte(domainContent), 0644); err != nil {
 		return fmt.Errorf("failed to create domain file: %v", err)
 	}
 
-	fmt.Printf("%s domain created in ./internal/core/domain/%s/%s.go", domain, fileName, fileName)
+	workspace.Printf("%s domain created in ./internal/core/domains/%s/%s.go\n", domain, fileName, fileName)
+
+	if err := manifest.Update(func(m *manifest.Manifest) {
+		m.Domain(domain).AddLayers(manifest.LayerDomain)
+	}); err != nil {
+		return err
+	}
+
+	// keep the domain list in README.md current
+	if err := project.WriteDocs(workspace.Root()); err != nil {
+		return fmt.Errorf("failed to update project docs: %v", err)
+	}
 
 	return nil
 }
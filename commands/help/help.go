@@ -0,0 +1,29 @@
+// commands/help/help.go
+package help
+
+import (
+	"fmt"
+
+	"github.com/rAlexander89/swan/nodes"
+	"github.com/rAlexander89/swan/plugins"
+)
+
+func init() {
+	nodes.RegisterCommand("help", Help)
+}
+
+// Help prints the help text for swan or for the command named by args
+func Help(args *nodes.Args) error {
+	root, err := plugins.LoadTree()
+	if err != nil {
+		return fmt.Errorf("error loading nodes: %v", err)
+	}
+
+	node, rest := root.Resolve(args.List("command"))
+	if len(rest) > 0 {
+		return fmt.Errorf("unknown command: %s", rest[0])
+	}
+
+	fmt.Print(node.Help())
+	return nil
+}
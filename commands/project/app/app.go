@@ -2,25 +2,52 @@ package app
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/rAlexander89/swan/genfile"
 )
 
-func WritePostgres(projectPath string) error {
-	postgresCode := postgresGoFile()
+// RepositoryDir returns the directory of the backend's repository package
+func RepositoryDir(projectPath string, db Database) string {
+	return filepath.Join(projectPath, "internal", "app", "repositories", db.Name)
+}
+
+// WriteDriver writes <db>.go, which opens and verifies the database connection
+func WriteDriver(projectPath string, db Database) error {
+	driverCode, err := render(driverGoFile(), db)
+	if err != nil {
+		return err
+	}
 
-	postgresPath := filepath.Join("internal", "app", "repositories", "postgres", "postgres.go")
+	driverPath := filepath.Join(RepositoryDir(projectPath, db), db.Name+".go")
 
-	if err := os.WriteFile(postgresPath, []byte(postgresCode), 0644); err != nil {
-		return fmt.Errorf("failed to write postgres.go: %v", err)
+	if err := genfile.WriteFile(db.Name, driverPath, []byte(driverCode), 0644); err != nil {
+		return fmt.Errorf("failed to write %s.go: %v", db.Name, err)
 	}
 
 	return nil
 }
 
-func postgresGoFile() string {
-	genCode := `// internal/app/repositories/postgres/postgres.go
-package postgres
+// render executes the code template tmpl for db
+func render(tmpl string, db Database) (string, error) {
+	t, err := template.New(db.Name).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %v", err)
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, db); err != nil {
+		return "", fmt.Errorf("failed to execute template: %v", err)
+	}
+
+	return b.String(), nil
+}
+
+func driverGoFile() string {
+	genCode := `// internal/app/repositories/{{.Name}}/{{.Name}}.go
+package {{.Name}}
 
 import (
     "context"
@@ -28,7 +55,7 @@ import (
     "fmt"
     "time"
     
-    _ "github.com/lib/pq"
+    _ "{{.Driver}}"
 )
 
 type Config struct {
@@ -41,23 +68,23 @@ type Config struct {
 
 func New(ctx context.Context, cfg Config) (*Connection, error) {
     if cfg.URI == "" {
-        return nil, fmt.Errorf("postgres uri cannot be empty")
+        return nil, fmt.Errorf("{{.Name}} uri cannot be empty")
     }
 
-    db, err := sql.Open("postgres", cfg.URI)
+    db, err := sql.Open("{{.DriverName}}", cfg.URI)
     if err != nil {
-        return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+        return nil, fmt.Errorf("failed to open {{.Name}} connection: %w", err)
     }
 
     // verify connection with a simple query
     var version string
-    err = db.QueryRowContext(ctx, "SELECT version()").Scan(&version)
+    err = db.QueryRowContext(ctx, "{{.VersionQuery}}").Scan(&version)
     if err != nil {
         db.Close()
-        return nil, fmt.Errorf("failed to verify postgres connection: %w", err)
+        return nil, fmt.Errorf("failed to verify {{.Name}} connection: %w", err)
     }
 
-    fmt.Printf("connected to postgres version %s",  version)
+    fmt.Printf("connected to {{.Name}} version %s",  version)
 
     // set connection pool settings
     db.SetMaxOpenConns(cfg.MaxOpenConnections)
@@ -74,22 +101,25 @@ func New(ctx context.Context, cfg Config) (*Connection, error) {
 	return genCode
 }
 
-func WritePostgresConnectionFile(projectPath string) error {
-	connContent := genPgConnCode()
+func WriteConnectionFile(projectPath string, db Database) error {
+	connContent, err := render(genConnCode(), db)
+	if err != nil {
+		return err
+	}
 
-	connectionPath := filepath.Join(projectPath, "internal", "app", "repositories", "postgres", "connection.go")
+	connectionPath := filepath.Join(RepositoryDir(projectPath, db), "connection.go")
 
-	if err := os.WriteFile(connectionPath, []byte(connContent), 0644); err != nil {
+	if err := genfile.WriteFile(db.Name+"-connection", connectionPath, []byte(connContent), 0644); err != nil {
 		return fmt.Errorf("failed to write connection.go: %v", err)
 	}
 
 	return nil
 }
 
-func genPgConnCode() string {
+func genConnCode() string {
 	connCode := `
-// internal/app/repositories/postgres/connection.go
-package postgres
+// internal/app/repositories/{{.Name}}/connection.go
+package {{.Name}}
 
 import (
     "context"
@@ -151,12 +181,15 @@ func (c *Connection) Close() error {
 	return connCode
 }
 
-func WriteRepository(projPath string) error {
-	repoContent := genRepositoryCode()
+func WriteRepository(projPath string, db Database) error {
+	repoContent, err := render(genRepositoryCode(), db)
+	if err != nil {
+		return err
+	}
 
-	repoPath := filepath.Join(projPath, "internal", "app", "repositories", "postgres", "repository.go")
+	repoPath := filepath.Join(RepositoryDir(projPath, db), "repository.go")
 
-	if err := os.WriteFile(repoPath, []byte(repoContent), 0644); err != nil {
+	if err := genfile.WriteFile(db.Name+"-repository", repoPath, []byte(repoContent), 0644); err != nil {
 		return fmt.Errorf("failed to write repository.go: %v", err)
 	}
 
@@ -165,8 +198,8 @@ func WriteRepository(projPath string) error {
 
 func genRepositoryCode() string {
 	repoCode := `
-// internal/app/repositories/postgres/repository.go
-package postgres
+// internal/app/repositories/{{.Name}}/repository.go
+package {{.Name}}
 
 import (
     "context"
@@ -186,14 +219,14 @@ var (
     once sync.Once
 )
 
-// NewRepository creates a singleton postgres repository
+// NewRepository creates a singleton {{.Name}} repository
 func NewRepository(ctx context.Context, cfg Config) (*Repository, error) {
     var initErr error
 
     once.Do(func() {
         conn, err := New(ctx, cfg)
         if err != nil {
-            initErr = fmt.Errorf("failed to initialize postgres connection: %w", err)
+            initErr = fmt.Errorf("failed to initialize {{.Name}} connection: %w", err)
             return
         }
 
@@ -5,14 +5,14 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
-	"os"
-	"path/filepath"
 	"strings"
 
+	"github.com/rAlexander89/swan/commands/project/app"
 	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
 )
 
-func generateCreate(domain string) (string, error) {
+func generateCreate(domain string, db app.Database) (string, error) {
 	structFields, sErr := getStructFields(domain)
 	if sErr != nil {
 		return "", fmt.Errorf("error reading struct fields for %s: %v ", domain, sErr)
@@ -31,54 +31,70 @@ func generateCreate(domain string) (string, error) {
 	domainTitle := utils.ToUpperFirst(domain)
 	domainTable := utils.ToSnakeCase(domain)
 
+	// the create stamps the timestamps the domain has
+	var timestamps []string
 	for i, field := range structFields {
 		columns = append(columns, utils.ToSnakeCase(field.Name))
-		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		placeholders = append(placeholders, db.Placeholder(i+1))
 		// use the original PascalCase field name from the struct
 		valueBindings = append(valueBindings, fmt.Sprintf("%s.%s", domainLower, field.Name))
+
+		if (field.Name == "CreatedAt" || field.Name == "UpdatedAt") && field.Type == "time.Time" {
+			timestamps = append(timestamps, fmt.Sprintf("%s.%s = now", domainLower, field.Name))
+		}
+	}
+
+	imports := `"context"`
+	stamp := ""
+	if len(timestamps) > 0 {
+		imports += "\n    \"time\""
+		stamp = "\n    now := time.Now().UTC()\n    " + strings.Join(timestamps, "\n    ") + "\n"
+	}
+
+	// a domain without fields still gets a row
+	values := "default values"
+	if db.Name == "mysql" {
+		values = "() values ()"
+	}
+	args := ""
+	if len(columns) > 0 {
+		values = fmt.Sprintf("(\n            %s\n        ) values (\n            %s\n        )",
+			strings.Join(columns, ",\n            "),
+			strings.Join(placeholders, ",\n            "))
+		args = "\n        " + strings.Join(valueBindings, ",\n        ") + ","
 	}
 
 	template := `package %[3]s
 
 import (
-    "context"
-    "time"
-    "%[2]s/internal/core/domains/%[3]s"
-    "%[2]s/internal/app/repositories/postgres"
+    %[6]s
 
+    "%[2]s/internal/core/domains/%[9]s"
 )
 
-func (r *postgres.Repository) Create%[4]s(ctx context.Context, %[3]s *%[3]s.%[4]s) error {
+func (r *Repository) Create%[4]s(ctx context.Context, %[3]s *%[3]s.%[4]s) error {
     query := ` + "`" + `
-        insert into %[5]ss (
-            %[6]s
-        ) values (
-            %[7]s
-        )
+        insert into %[5]ss %[7]s
     ` + "`" + `
-
-    now := time.Now().UTC()
-    %[3]s.CreatedAt = now
-    %[3]s.UpdatedAt = now
-
+%[8]s
     _, err := r.conn.ExecContext(
         ctx,
-        query,
-        %[8]s,
+        query,%[1]s
     )
 
     return err
 }`
 
 	return fmt.Sprintf(template,
-		domain,                                   // [1]
-		projectName,                              // [2]
-		domainLower,                              // [3]
-		domainTitle,                              // [4]
-		domainTable,                              // [5]
-		strings.Join(columns, ",\n            "), // [6]
-		strings.Join(placeholders, ",\n            "), // [7]
-		strings.Join(valueBindings, ",\n        "),    // [8]
+		args,                        // [1]
+		projectName,                 // [2]
+		domainLower,                 // [3]
+		domainTitle,                 // [4]
+		domainTable,                 // [5]
+		imports,                     // [6]
+		values,                      // [7]
+		stamp,                       // [8]
+		utils.PascalToSnake(domain), // [9]
 	), nil
 }
 
@@ -89,22 +105,16 @@ type Field struct {
 }
 
 func getStructFields(domain string) ([]Field, error) { // ex User
-	pwd, err := os.Getwd()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get working directory: %v", err)
-	}
-
 	// convert domain name to snake_case for file path
-	domainPath := filepath.Join(
-		pwd,
+	domainPath := workspace.Path(
 		"internal",
 		"core",
 		"domains",
-		strings.ToLower(domain),
+		utils.PascalToSnake(domain),
 		fmt.Sprintf("%s.go", utils.PascalToSnake(domain)),
 	)
 
-	content, err := os.ReadFile(domainPath)
+	content, err := workspace.ReadFile(domainPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read domain file: %v", err)
 	}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rAlexander89/swan/commands/project/app"
+	"github.com/rAlexander89/swan/utils"
+)
+
+// generateRepository returns the repository type of domain. it runs its queries
+// on the connection of the db backend and implements the domain's repository
+// port, one <domain>_<operation>.go file per operation
+func generateRepository(domain string, db app.Database) (string, error) {
+	if domain == "" {
+		return "", fmt.Errorf("domain name cannot be empty")
+	}
+
+	// get project name for imports
+	projectName, err := utils.GetProjectName()
+	if err != nil {
+		return "", fmt.Errorf("failed to get project name: %v", err)
+	}
+
+	// normalize domain names for different uses
+	domainLower := strings.ToLower(domain)
+	domainTitle := utils.ToUpperFirst(domain)
+
+	code := fmt.Sprintf(`package %[2]s
+
+import (
+    "%[1]s/internal/app/repositories/%[4]s"
+    "%[1]s/internal/core/ports/repository"
+)
+
+// Repository stores %[3]s values in %[4]s
+type Repository struct {
+    conn *%[4]s.Connection
+}
+
+// New returns the %[3]s repository running on the shared %[4]s connection
+func New(r *%[4]s.Repository) *Repository {
+    return &Repository{
+        conn: r.GetConnection(),
+    }
+}
+
+// services depend on the port, not on this type
+var _ repository.%[3]sRepository = (*Repository)(nil)
+`,
+		projectName, // [1]
+		domainLower, // [2]
+		domainTitle, // [3]
+		db.Name,     // [4]
+	)
+
+	return code, nil
+}
@@ -3,14 +3,19 @@ package db
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/rAlexander89/swan/commands/project"
+	"github.com/rAlexander89/swan/commands/project/app"
 	"github.com/rAlexander89/swan/commands/project/port"
 	"github.com/rAlexander89/swan/commands/project/service"
+	"github.com/rAlexander89/swan/genfile"
+	"github.com/rAlexander89/swan/manifest"
 	"github.com/rAlexander89/swan/nodes"
 	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/wizard"
+	"github.com/rAlexander89/swan/workspace"
 )
 
 // operation flags
@@ -24,6 +29,27 @@ const (
 
 func init() {
 	nodes.RegisterCommand("hatch", Hatch)
+	wizard.RegisterStep("hatch", askOperations)
+}
+
+// askOperations asks which CRUDI operations to generate
+func askOperations(p *wizard.Prompter, args *nodes.Args) ([]string, error) {
+	if args.Has("operations") {
+		return nil, nil
+	}
+
+	ops, err := p.Checklist("operations to generate", []wizard.Option{
+		{Key: string(Create), Label: "create"},
+		{Key: string(Read), Label: "read"},
+		{Key: string(Update), Label: "update"},
+		{Key: string(Delete), Label: "delete"},
+		{Key: string(Index), Label: "index"},
+	}, []string{"C", "R", "U", "D", "I"})
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{"-c", strings.Join(ops, "")}, nil
 }
 
 type operation struct {
@@ -32,55 +58,57 @@ type operation struct {
 	content  string
 }
 
-func Hatch(args []string) error {
-	if len(args) < 1 {
+func Hatch(args *nodes.Args) error {
+	domain := args.String("domain")
+	if domain == "" {
 		return fmt.Errorf("domain name required")
 	}
 
-	domain := args[0]
-	ops := "CRUDI"
-
-	// check for operations flag
-	for i, arg := range args {
-		if arg == "-c" && i+1 < len(args) {
-			ops = strings.ToUpper(args[i+1])
-			break
+	// swan.json knows the backend and the domains. older projects are postgres
+	backend := "postgres"
+	m, err := manifest.LoadOptional()
+	if err != nil {
+		return err
+	}
+	if m != nil {
+		if !m.HasDomain(domain) {
+			return fmt.Errorf("domain %s is not in %s, create it with swan domain %s", domain, manifest.FileName, domain)
 		}
+		backend = m.Database
 	}
 
-	// validate operations
-	for _, op := range ops {
-		switch op {
-		case Create, Read, Update, Delete, Index:
-			continue
-		default:
-			return fmt.Errorf("invalid operation: %c", op)
-		}
+	// operations flag defaults to CRUDI. swan.json records the operations
+	// that were generated, not the requested ones
+	ops, err := project.CheckOps(args.String("operations"))
+	if err != nil {
+		return err
 	}
 
-	// get project root and create paths
-	pwd, err := os.Getwd()
+	db, hasDB, err := app.LookupDatabase(backend)
 	if err != nil {
-		return fmt.Errorf("failed to get working directory: %v", err)
+		return err
+	}
+	if !hasDB {
+		return fmt.Errorf("project has no database, hatch needs one to generate a repository")
 	}
 
 	domain_snake := utils.PascalToSnake(domain)
 
-	// 1. postgres repository implementation
-	repoPath := filepath.Join(pwd, "internal", "app", "repositories", "postgres", "domains", domain_snake)
-	if err := os.MkdirAll(repoPath, 0755); err != nil {
+	// 1. repository implementation
+	repoPath := filepath.Join(app.RepositoryDir(workspace.Root(), db), "domains", domain_snake)
+	if err := workspace.MkdirAll(repoPath, 0755); err != nil {
 		return fmt.Errorf("failed to create repository directory: %v", err)
 	}
 
 	// generate files based on operations
 	operations := []operation{}
 
-	persistenceContnent, pErr := generateRepositoryInterface(domain, ops)
+	persistenceContnent, pErr := generateRepository(domain, db)
 	if pErr != nil {
 		return pErr
 	}
 
-	// always create repository interface
+	// always create the repository type the operations are methods of
 	operations = append(operations, operation{
 		name:     "repository",
 		filename: fmt.Sprintf("%s_repository.go", domain_snake),
@@ -92,7 +120,7 @@ func Hatch(args []string) error {
 		switch op {
 		case Create:
 
-			content, cErr := generateCreate(domain)
+			content, cErr := generateCreate(domain, db)
 			if cErr != nil {
 				return scaffoldErr(domain, op)
 			}
@@ -129,19 +157,14 @@ func Hatch(args []string) error {
 		}
 	}
 
-	// writes postgres > domain_repository file
+	// writes <backend> > domain_repository file
 	for _, op := range operations {
 		path := filepath.Join(repoPath, op.filename)
-		if err := os.WriteFile(path, []byte(op.content), 0644); err != nil {
+		if err := genfile.WriteFile("repository-"+op.name, path, []byte(op.content), 0644); err != nil {
 			return fmt.Errorf("failed to write %s: %v", op.name, err)
 		}
 	}
 
-	// generate port > repository > domain file
-	if err := port.GenerateRepositoryPort(domain); err != nil {
-		return err
-	}
-
 	// 2. repository port interface
 	if err := port.GenerateRepositoryPort(domain); err != nil {
 		return fmt.Errorf("failed to generate repository port: %v", err)
@@ -152,7 +175,18 @@ func Hatch(args []string) error {
 		return fmt.Errorf("failed to generate service: %v", err)
 	}
 
-	return nil
+	if err := manifest.Update(func(m *manifest.Manifest) {
+		d := m.Domain(domain)
+		d.AddLayers(manifest.LayerRepository, manifest.LayerPort, manifest.LayerService)
+		d.AddOps(ops)
+	}); err != nil {
+		return err
+	}
+
+	if args.Bool("no-verify") {
+		return nil
+	}
+	return project.Verify(workspace.Root())
 }
 
 func scaffoldErr(domain string, op rune) error {
@@ -0,0 +1,96 @@
+package project
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/rAlexander89/swan/commands/project/app"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+// getDriver adds the backend's driver to the project's go.mod. online it runs
+// go get; offline it resolves the pinned version from the local module cache
+// only, and when that isn't possible writes the require line by hand. pending
+// reports whether the driver still has to be downloaded
+func getDriver(projectPath string, db app.Database, offline bool) (pending bool, err error) {
+	if !offline {
+		output, err := workspace.Command(projectPath, "go", "get", db.Driver)
+		if err != nil {
+			return false, fmt.Errorf("failed to get %s driver: %v\noutput: %s", db.Name, err, string(output))
+		}
+		return false, nil
+	}
+
+	module := db.Driver + "@" + db.Version
+	if inModuleCache(db.Driver, db.Version) {
+		err := cachedGet(projectPath, module)
+		if err == nil {
+			workspace.Verbosef("resolved %s from the module cache\n", module)
+			return false, nil
+		}
+		workspace.Verbosef("could not resolve %s from the module cache: %v\n", module, err)
+	}
+
+	if err := requireModule(projectPath, db.Driver, db.Version); err != nil {
+		return false, fmt.Errorf("failed to pin %s driver: %v", db.Name, err)
+	}
+	return true, nil
+}
+
+// cachedGet runs go get with the module proxy disabled, so it succeeds only if
+// the module and everything it needs are already in the module cache
+func cachedGet(projectPath, module string) error {
+	output, err := workspace.CommandEnv(projectPath, []string{"GOPROXY=off", "GOFLAGS=-mod=mod"}, "go", "get", module)
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// requireModule appends a require line for path at version to go.mod
+func requireModule(projectPath, path, version string) error {
+	goModPath := filepath.Join(projectPath, "go.mod")
+	goMod, err := workspace.ReadFile(goModPath)
+	if err != nil {
+		return err
+	}
+
+	content := strings.TrimRight(string(goMod), "\n")
+	content += fmt.Sprintf("\n\nrequire %s %s\n", path, version)
+	return workspace.WriteFile(goModPath, []byte(content), 0644)
+}
+
+// inModuleCache reports whether the zip of path at version has been downloaded
+// to the local module cache
+func inModuleCache(path, version string) bool {
+	output, err := exec.Command("go", "env", "GOMODCACHE").Output()
+	if err != nil {
+		return false
+	}
+
+	cache := strings.TrimSpace(string(output))
+	if cache == "" {
+		return false
+	}
+
+	zip := filepath.Join(cache, "cache", "download", escapeModulePath(path), "@v", escapeModulePath(version)+".zip")
+	return workspace.Exists(zip)
+}
+
+// escapeModulePath applies the module cache's case encoding, where each upper
+// case letter is replaced by ! and its lower case form
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			b.WriteRune('!')
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
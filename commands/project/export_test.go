@@ -0,0 +1,5 @@
+// commands/project/export_test.go
+package project
+
+// InModuleCache lets the tests skip the projects whose driver would have to be downloaded
+var InModuleCache = inModuleCache
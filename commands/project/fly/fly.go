@@ -4,73 +4,132 @@ package fly
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 
-	project "github.com/rAlexander89/swan/commands/project/handlers"
+	"github.com/rAlexander89/swan/commands/project"
+	"github.com/rAlexander89/swan/commands/project/app"
+	handlers "github.com/rAlexander89/swan/commands/project/handlers"
 	routes "github.com/rAlexander89/swan/commands/project/routes"
+	"github.com/rAlexander89/swan/manifest"
 	"github.com/rAlexander89/swan/nodes"
 	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
 )
 
 func init() {
 	nodes.RegisterCommand("fly", Fly)
 }
 
-func Fly(args []string) error {
-	if len(args) < 1 {
+func Fly(args *nodes.Args) error {
+	domain := args.String("domain")
+	if domain == "" {
 		return fmt.Errorf("domain name required")
 	}
 
-	domain := args[0]
-	var ops string
+	// swan.json knows the domains and what hatch generated for them. older
+	// projects are checked for the domain struct and are postgres
+	m, err := manifest.LoadOptional()
+	if err != nil {
+		return err
+	}
 
-	// check for operations flag
-	for i, arg := range args {
-		if arg == "-c" && i+1 < len(args) {
-			ops = strings.ToUpper(args[i+1])
+	backend := "postgres"
+	ops := "C"
+	if m != nil {
+		if !m.HasDomain(domain) {
+			return fmt.Errorf("domain %s is not in %s, create it with swan domain %s", domain, manifest.FileName, domain)
+		}
 
-			// for now, only support Create
-			if !strings.Contains(ops, "C") {
-				return fmt.Errorf("currently only Create operation is supported")
-			}
-			break
+		d := m.Domains[domain]
+		if !d.HasLayer(manifest.LayerService) {
+			return fmt.Errorf("domain %s has no service yet, generate it with swan hatch %s", domain, domain)
+		}
+		if d.Ops != "" {
+			ops = d.Ops
+		}
+		backend = m.Database
+	} else {
+		domainPath := workspace.Path(
+			"internal",
+			"core",
+			"domains",
+			utils.PascalToSnake(domain),
+			fmt.Sprintf("%s.go", utils.PascalToSnake(domain)),
+		)
+
+		if !workspace.Exists(domainPath) {
+			return fmt.Errorf("domain %s not found at %s", domain, domainPath)
 		}
 	}
 
-	pwd, err := os.Getwd()
+	// operations default to the ones hatch generated. swan.json records the
+	// operations that were generated, not the requested ones
+	if args.Has("operations") {
+		ops = args.String("operations")
+	}
+	ops, err = project.CheckOps(ops)
 	if err != nil {
-		return fmt.Errorf("failed to get working directory: %v", err)
+		return err
 	}
 
-	// check if domain struct exists
-	domainPath := filepath.Join(
-		pwd,
-		"internal",
-		"core",
-		"domains",
-		strings.ToLower(domain),
-		fmt.Sprintf("%s.go", utils.PascalToSnake(domain)),
-	)
-
-	if _, err := os.Stat(domainPath); os.IsNotExist(err) {
-		return fmt.Errorf("domain %s not found at %s", domain, domainPath)
+	db, hasDB, err := app.LookupDatabase(backend)
+	if err != nil {
+		return err
+	}
+	if !hasDB {
+		return fmt.Errorf("project has no database, fly needs the repository hatch generates")
 	}
 
 	// generate handler
-	if err := project.WriteHandler(".", domain, ops); err != nil {
+	if err := handlers.WriteHandler(workspace.Root(), domain, ops); err != nil {
 		return fmt.Errorf("error generating handler: %v", err)
 	}
 
 	// generate routes
-	if err := routes.WriteRoutes(".", domain, ops); err != nil {
+	if err := routes.WriteRoutes(workspace.Root(), domain, ops); err != nil {
 		return fmt.Errorf("error generating routes: %v", err)
 	}
 
-	// register with api routes
-	if err := routes.WriteTopLevelRoutes(".", domain, ops); err != nil {
+	// register with api routes, next to the domains flown before
+	if err := routes.WriteTopLevelRoutes(workspace.Root(), db, routedDomains(m, domain)); err != nil {
 		return fmt.Errorf("error registering routes: %v", err)
 	}
 
-	return nil
+	if err := manifest.Update(func(m *manifest.Manifest) {
+		d := m.Domain(domain)
+		d.AddLayers(manifest.LayerHandler, manifest.LayerRoutes)
+		d.AddOps(ops)
+	}); err != nil {
+		return err
+	}
+
+	if args.Bool("no-verify") {
+		return nil
+	}
+	return project.Verify(workspace.Root())
+}
+
+// routedDomains lists the domains routes.go registers: domain and those with
+// routes in swan.json or, for projects without one, a routes package
+func routedDomains(m *manifest.Manifest, domain string) []routes.DomainRoutes {
+	var names []string
+	if m != nil {
+		for _, name := range m.DomainNames() {
+			if m.Domains[name].HasLayer(manifest.LayerRoutes) && name != domain {
+				names = append(names, name)
+			}
+		}
+	} else {
+		entries, _ := os.ReadDir(workspace.Path("internal", "infrastructure", "server", "routes"))
+		for _, entry := range entries {
+			if name := utils.SnakeToPascal(entry.Name()); entry.IsDir() && name != domain {
+				names = append(names, name)
+			}
+		}
+	}
+
+	domains := make([]routes.DomainRoutes, 0, len(names)+1)
+	for _, name := range append(names, domain) {
+		domains = append(domains, routes.NewDomainRoutes(name))
+	}
+	return domains
 }
@@ -2,93 +2,112 @@ package project
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
+	"strings"
+	"text/template"
 
 	"github.com/rAlexander89/swan/commands/project/app"
+	"github.com/rAlexander89/swan/genfile"
 	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
 )
 
-func getPostgresDriver() error {
-	cmd := exec.Command("go", "get", "github.com/lib/pq")
-	_, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("failed to get postgres driver: %v", err)
-	}
-	return nil
-}
-
-func WriteAppModule(projectPath string) error {
+// WriteAppModule writes internal/app/app.go and, unless dbName is none, the
+// repository package of the chosen database backend. the driver itself is
+// added to go.mod by getDriver
+func WriteAppModule(projectPath, dbName string) error {
 	projectName, pErr := utils.GetProjectName()
 	if pErr != nil {
 		return pErr
 	}
 
-	if err := app.WritePostgres(projectName); err != nil {
-		return err
-	}
-
-	if err := app.WritePostgresConnectionFile(projectPath); err != nil {
-		return err
-	}
-	if err := app.WriteRepository(projectPath); err != nil {
+	db, hasDB, err := app.LookupDatabase(dbName)
+	if err != nil {
 		return err
 	}
 
-	if err := getPostgresDriver(); err != nil {
-		return err
+	if hasDB {
+		if err := workspace.MkdirAll(app.RepositoryDir(projectPath, db), 0755); err != nil {
+			return fmt.Errorf("failed to create %s repository directory: %v", db.Name, err)
+		}
+
+		if err := app.WriteDriver(projectPath, db); err != nil {
+			return err
+		}
+
+		if err := app.WriteConnectionFile(projectPath, db); err != nil {
+			return err
+		}
+		if err := app.WriteRepository(projectPath, db); err != nil {
+			return err
+		}
 	}
 
 	shutdownFuncStr := `
     func (a *App) Shutdown() error {
-        if a.postgresDB != nil {
-            if err := a.postgresDB.Close(); err != nil {
-                return fmt.Errorf("error closing postgres connection: %w", err)
+        return nil
+    }
+    `
+	if hasDB {
+		shutdownFuncStr = `
+    func (a *App) Shutdown() error {
+        if a.{{.DB.Field}} != nil {
+            if err := a.{{.DB.Field}}.Close(); err != nil {
+                return fmt.Errorf("error closing {{.DB.Name}} connection: %w", err)
             }
         }
         return nil
     }
     `
+	}
 
 	onceFuncStr := `
     once.Do(func() {
-        // initialize postgres repository
-        postgresConfig := postgres.Config{
-            URI:                   cfg.DB.Postgres.URI,
-            MaxOpenConnections:    cfg.DB.Postgres.MaxOpenConnections,
-            MaxIdleConnections:    cfg.DB.Postgres.MaxIdleConnections,
-            MaxConnectionIdleTime: cfg.DB.Postgres.MaxConnectionIdleTime,
-            MaxConnectionLifetime: cfg.DB.Postgres.MaxConnectionLifetime,
+        app = &App{
+            config: cfg,
+        }
+    })
+    `
+	if hasDB {
+		onceFuncStr = `
+    once.Do(func() {
+        // initialize {{.DB.Name}} repository
+        {{.DB.Name}}Config := {{.DB.Name}}.Config{
+            URI:                   cfg.DB.{{.DB.Title}}.URI,
+            MaxOpenConnections:    cfg.DB.{{.DB.Title}}.MaxOpenConnections,
+            MaxIdleConnections:    cfg.DB.{{.DB.Title}}.MaxIdleConnections,
+            MaxConnectionIdleTime: cfg.DB.{{.DB.Title}}.MaxConnectionIdleTime,
+            MaxConnectionLifetime: cfg.DB.{{.DB.Title}}.MaxConnectionLifetime,
         }
 
-        postgresDB, err := postgres.NewRepository(ctx, postgresConfig)
+        {{.DB.Field}}, err := {{.DB.Name}}.NewRepository(ctx, {{.DB.Name}}Config)
         if err != nil {
-            fmt.Errorf("failed to initialize postgres repository: %w", err)
+            initErr = fmt.Errorf("failed to initialize {{.DB.Name}} repository: %w", err)
             return
         }
 
         app = &App{
             config:     cfg,
-            postgresDB: postgresDB,
+            {{.DB.Field}}: {{.DB.Field}},
         }
     })
     `
+	}
 
-	appContent := fmt.Sprintf(`package app
+	appTmpl := `package app
 
 import (
     "context"
     "sync"
     "fmt"
-
-    "%s/internal/app/repositories/postgres"
-    "%s/internal/infrastructure/config"
+{{if .HasDB}}
+    "{{.ProjectName}}/internal/app/repositories/{{.DB.Name}}"{{end}}
+    "{{.ProjectName}}/internal/infrastructure/config"
 )
 
 type App struct {
-    config     *config.Config
-    postgresDB *postgres.Repository
+    config     *config.Config{{if .HasDB}}
+    {{.DB.Field}} *{{.DB.Name}}.Repository{{end}}
 }
 
 var (
@@ -101,10 +120,8 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
         return nil, fmt.Errorf("config cannot be nil")
     }
 
-    %s
-
     var initErr error
-    
+    ` + onceFuncStr + `
     if initErr != nil {
         return nil, initErr
     }
@@ -115,17 +132,35 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 func (a *App) Config() *config.Config {
     return a.config
 }
-
-func (a *App) PostgresDB() *postgres.Repository {
-    return a.postgresDB
+{{if .HasDB}}
+func (a *App) {{.DB.Title}}DB() *{{.DB.Name}}.Repository {
+    return a.{{.DB.Field}}
 }
+{{end}}
+` + shutdownFuncStr + `
+`
 
-%s
-`, projectName, projectName, onceFuncStr, shutdownFuncStr)
+	tmpl, err := template.New("app").Parse(appTmpl)
+	if err != nil {
+		return fmt.Errorf("failed to parse app template: %v", err)
+	}
+
+	var appContent strings.Builder
+	if err := tmpl.Execute(&appContent, struct {
+		ProjectName string
+		HasDB       bool
+		DB          app.Database
+	}{
+		ProjectName: projectName,
+		HasDB:       hasDB,
+		DB:          db,
+	}); err != nil {
+		return fmt.Errorf("failed to execute app template: %v", err)
+	}
 
 	appPath := filepath.Join(projectPath, "internal", "app", "app.go")
 
-	if err := os.WriteFile(appPath, []byte(appContent), 0644); err != nil {
+	if err := genfile.WriteFile("app", appPath, []byte(appContent.String()), 0644); err != nil {
 		return fmt.Errorf("failed to write app.go: %v", err)
 	}
 
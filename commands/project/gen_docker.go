@@ -0,0 +1,184 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+
+	"github.com/rAlexander89/swan/commands/project/app"
+	"github.com/rAlexander89/swan/genfile"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+const dockerfileTmpl = `# build stage
+FROM golang:{{.GoVersion}}-alpine AS build
+WORKDIR /src
+
+COPY go.mod go.sum* ./
+RUN go mod download
+
+COPY . .
+RUN CGO_ENABLED=0 go build -o /bin/app ./cmd
+
+# run stage
+FROM gcr.io/distroless/static-debian12
+{{- if .HasConfigs}}
+
+# configs/ is embedded in the binary. mount a file and set CONFIG_PATH to
+# use another one
+ENV ENV=prod
+{{- end}}
+COPY --from=build /bin/app /bin/app
+{{- if .Server}}
+
+# the server port of configs/prod.json
+EXPOSE {{.Port}}
+{{- end}}
+ENTRYPOINT ["/bin/app"]
+`
+
+// the database service uses the defaults written to configs/dev.json, so the
+// app run with make run connects to it as is
+const composeTmpl = `services:
+  db:
+    image: {{.DB.Image}}
+    environment:
+{{- range $key, $value := .DB.ContainerEnv}}
+      {{$key}}: {{$value}}
+{{- end}}
+    ports:
+      - "{{.DB.Port}}:{{.DB.Port}}"
+    volumes:
+      - db-data:{{.DB.DataDir}}
+
+volumes:
+  db-data:
+`
+
+const makefileTmpl = `.PHONY: run test migrate lint{{if .HasCompose}} up down{{end}}
+
+ENV ?= dev
+{{- if .HasDB}}
+DATABASE_URL ?= {{.DB.MigrateURL}}
+{{- end}}
+
+run:
+	ENV=$(ENV) go run ./cmd
+
+test:
+	go test ./...
+
+migrate:
+{{- if .HasDB}}
+	migrate -path db/migrations -database "$(DATABASE_URL)" up
+{{- else}}
+	@echo "no database configured"
+{{- end}}
+
+lint:
+	go vet ./...
+	@if command -v golangci-lint >/dev/null; then golangci-lint run; else echo "golangci-lint not installed, skipped"; fi
+{{- if .HasCompose}}
+
+up:
+	docker compose up -d
+
+down:
+	docker compose down
+{{- end}}
+`
+
+// WriteContainerFiles writes a Dockerfile, a Makefile and, for backends with a
+// server, a docker-compose.yml running the database with the dev credentials
+func WriteContainerFiles(projectPath, dbName string) error {
+	db, hasDB, err := app.LookupDatabase(dbName)
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		GoVersion  string
+		HasConfigs bool
+		Server     bool
+		Port       int
+		HasDB      bool
+		HasCompose bool
+		DB         app.Database
+	}{
+		GoVersion:  goVersion(projectPath),
+		HasConfigs: workspace.Exists(filepath.Join(projectPath, "configs")),
+		Server:     workspace.Exists(filepath.Join(projectPath, "internal", "infrastructure", "server")),
+		Port:       serverPort(projectPath),
+		HasDB:      hasDB,
+		HasCompose: hasDB && db.Image != "",
+		DB:         db,
+	}
+
+	files := []struct {
+		generator string
+		name      string
+		tmpl      string
+		write     bool
+	}{
+		{"dockerfile", "Dockerfile", dockerfileTmpl, true},
+		{"compose", "docker-compose.yml", composeTmpl, data.HasCompose},
+		{"makefile", "Makefile", makefileTmpl, true},
+	}
+
+	for _, f := range files {
+		if !f.write {
+			continue
+		}
+
+		tmpl, err := template.New(f.name).Parse(f.tmpl)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s template: %v", f.name, err)
+		}
+
+		if err := genfile.WriteTemplate(f.generator, filepath.Join(projectPath, f.name), tmpl, data); err != nil {
+			return fmt.Errorf("failed to write %s: %v", f.name, err)
+		}
+	}
+
+	return nil
+}
+
+// serverPort returns the server port of configs/prod.json, which the image
+// runs with, or the default port
+func serverPort(projectPath string) int {
+	data, err := workspace.ReadFile(filepath.Join(projectPath, "configs", "prod.json"))
+	if err != nil {
+		return DefaultServerPort
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil || cfg.Server == nil || cfg.Server.Port == 0 {
+		return DefaultServerPort
+	}
+	return cfg.Server.Port
+}
+
+// goVersion returns the major.minor go version of the project's go.mod, or of
+// the toolchain swan was built with when go.mod has no go line
+func goVersion(projectPath string) string {
+	version := strings.TrimPrefix(runtime.Version(), "go")
+
+	if goMod, err := workspace.ReadFile(filepath.Join(projectPath, "go.mod")); err == nil {
+		for _, line := range strings.Split(string(goMod), "\n") {
+			if v, found := strings.CutPrefix(strings.TrimSpace(line), "go "); found && len(strings.Fields(v)) > 0 {
+				version = strings.Fields(v)[0]
+				break
+			}
+		}
+	}
+
+	// golang images are tagged by major.minor
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
@@ -0,0 +1,327 @@
+package project
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/rAlexander89/swan/genfile"
+	"github.com/rAlexander89/swan/manifest"
+	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+// the part of README.md between these markers belongs to swan and is rewritten
+// as the project grows. everything around it is left alone
+const (
+	readmeBegin = "<!-- swan:begin -->"
+	readmeEnd   = "<!-- swan:end -->"
+)
+
+// EnvVar is an environment variable understood by the generated config package
+type EnvVar struct {
+	Name        string
+	Default     string
+	Description string
+	Override    bool // overrides a config field, so it is optional
+}
+
+// EnvVars returns the environment variables the generated config package of
+// the project reads: ENV, and one override per field of configs/dev.json
+func EnvVars(projectPath string) ([]EnvVar, error) {
+	vars := []EnvVar{
+		{Name: "ENV", Default: "dev", Description: "config environment, selects configs/<ENV>.json"},
+	}
+
+	data, err := workspace.ReadFile(filepath.Join(projectPath, "configs", "dev.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return vars, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configs/dev.json: %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var cfg map[string]any
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse configs/dev.json: %v", err)
+	}
+
+	return append(vars, overrideVars(EnvPrefix, "", cfg)...), nil
+}
+
+// overrideVars lists the variables of the fields under section, sorted by name
+func overrideVars(prefix, section string, fields map[string]any) []EnvVar {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var vars []EnvVar
+	for _, key := range keys {
+		name := prefix + "_" + strings.ToUpper(key)
+		path := strings.TrimPrefix(section+"."+key, ".")
+
+		switch value := fields[key].(type) {
+		case map[string]any:
+			vars = append(vars, overrideVars(name, path, value)...)
+		case string, json.Number, bool:
+			vars = append(vars, EnvVar{
+				Name:        name,
+				Default:     fmt.Sprint(value),
+				Description: "overrides " + path,
+				Override:    true,
+			})
+		case []any:
+			// lists are comma separated
+			items := make([]string, len(value))
+			for i, item := range value {
+				items[i] = fmt.Sprint(item)
+			}
+			vars = append(vars, EnvVar{
+				Name:        name,
+				Default:     strings.Join(items, ","),
+				Description: "overrides " + path + ", comma separated",
+				Override:    true,
+			})
+		}
+	}
+
+	return vars
+}
+
+const readmeTmpl = `# {{.Name}}
+
+` + readmeBegin + `
+` + "`{{.Module}}`" + ` was generated by [swan](https://github.com/rAlexander89/swan).
+
+## running
+
+` + "```" + `
+ENV=dev go run ./cmd
+` + "```" + `
+{{if .Configs}}
+` + "`ENV`" + ` picks the config file in configs/ (dev, stg or prod). Any config field can be overridden by an environment variable: ` + "`" + EnvPrefix + "_`" + ` plus the field's json path in upper case, joined by underscores. In dev, .env is loaded first, and variables already set in the environment win. See .env.example for every variable the config package reads.
+
+The config file is the first found of: the ` + "`--config`" + ` flag, the ` + "`CONFIG_PATH`" + ` environment variable (either may be a file or a directory holding <ENV>.json), configs/<ENV>.json in the working directory, and the configs embedded in the binary at build time.
+{{- if .Layer "internal/infrastructure/server"}}
+
+The server listens on the host and port of the ` + "`server`" + ` section of the config file. dev listens on localhost only, the other environments on every interface. The read, write and idle timeouts and the shutdown grace are in seconds. A zero timeout means none, and the shutdown grace is how long requests in flight get to finish after SIGTERM.
+{{- end}}
+{{end}}
+## layout
+
+| path | holds |
+| --- | --- |
+| cmd/ | the main package |
+{{- if .Configs}}
+| configs/ | one json config per environment |
+{{- end}}
+{{- if .Layer "internal/core/domains"}}
+| internal/core/domains/ | domain types, one package per domain |
+{{- end}}
+{{- if .Layer "internal/core/ports"}}
+| internal/core/ports/ | interfaces the services depend on, e.g. repositories |
+{{- end}}
+{{- if .Layer "internal/core/services"}}
+| internal/core/services/ | business logic per domain |
+{{- end}}
+{{- if .Layer "internal/app"}}
+| internal/app/ | the app module, repositories{{if .Layer "internal/app/handlers"}} and handlers{{end}} |
+{{- end}}
+{{- if .Layer "internal/infrastructure"}}
+| internal/infrastructure/ | config loading{{if .Layer "internal/infrastructure/server"}}, the http server and routes{{end}} |
+{{- end}}
+{{- if .Layer "db/migrations"}}
+| db/migrations/ | sql migrations |
+{{- end}}
+
+## domains
+{{if .Domains}}
+{{range .Domains}}- {{.}}
+{{end}}{{else}}
+none yet.
+{{end}}
+## extending
+
+` + "```" + `
+swan domain <Domain> -f <Field> <type> ...   # add a domain type
+swan hatch <Domain> -c CRUDI                 # generate its repository, port and service
+swan fly <Domain> -c CRUDI                   # generate its http handler and routes
+swan config check [env] [--with-env]         # validate configs/*.json
+swan config add <path:type=default> ...      # add a config field and its defaults
+` + "```" + `
+` + readmeEnd + `
+`
+
+// the overrides are commented out: configs/<ENV>.json holds their defaults
+const envExampleTmpl = `# copy to .env and adjust. .env is loaded in dev, variables set in the
+# environment win over it
+{{range .}}
+# {{.Description}}
+{{if .Override}}# {{end}}{{.Name}}={{.Default}}
+{{end}}`
+
+// readmeData describes the project for README.md
+type readmeData struct {
+	Name    string
+	Module  string
+	Configs bool
+	Domains []string
+	root    string
+}
+
+// Layer reports whether the project has the directory rel
+func (d readmeData) Layer(rel string) bool {
+	return workspace.Exists(filepath.Join(d.root, filepath.FromSlash(rel)))
+}
+
+// WriteDocs writes README.md, .env.example, and .env when it is still empty, and
+// a .gitignore that keeps .env out of git
+func WriteDocs(projectPath string) error {
+	if err := WriteReadme(projectPath); err != nil {
+		return err
+	}
+	if err := WriteEnvExample(projectPath); err != nil {
+		return err
+	}
+	return WriteGitignore(projectPath)
+}
+
+// gitignoreContent keeps the local values of .env, e.g. credentials, out of git
+const gitignoreContent = `# local values and credentials, see .env.example
+.env
+`
+
+// WriteGitignore writes a .gitignore listing .env. an existing .gitignore keeps
+// its content and gets .env appended if it doesn't list it
+func WriteGitignore(projectPath string) error {
+	path := filepath.Join(projectPath, ".gitignore")
+
+	existing, err := workspace.ReadFile(path)
+	if err != nil || len(bytes.TrimSpace(existing)) == 0 {
+		if err := genfile.WriteFile("gitignore", path, []byte(gitignoreContent), 0644); err != nil {
+			return fmt.Errorf("failed to write .gitignore: %v", err)
+		}
+		return nil
+	}
+
+	for _, line := range strings.Split(string(existing), "\n") {
+		switch strings.TrimSpace(line) {
+		case ".env", "/.env":
+			return nil
+		}
+	}
+
+	updated := string(existing)
+	if !strings.HasSuffix(updated, "\n") {
+		updated += "\n"
+	}
+	updated += "\n" + gitignoreContent
+	if err := workspace.WriteFile(path, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write .gitignore: %v", err)
+	}
+
+	workspace.Printf("added .env to .gitignore\n")
+	return nil
+}
+
+// WriteReadme writes the swan section of README.md. an existing README keeps
+// everything outside the section; one without the section is left alone
+func WriteReadme(projectPath string) error {
+	module, err := utils.GetProjectName()
+	if err != nil {
+		return err
+	}
+
+	data := readmeData{
+		Name:    filepath.Base(projectPath),
+		Module:  module,
+		Configs: workspace.Exists(filepath.Join(projectPath, "configs")),
+		Domains: manifest.Domains(),
+		root:    projectPath,
+	}
+
+	tmpl, err := template.New("readme").Parse(readmeTmpl)
+	if err != nil {
+		return fmt.Errorf("failed to parse readme template: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute readme template: %v", err)
+	}
+	content := buf.String()
+
+	readmePath := filepath.Join(projectPath, "README.md")
+	if existing, err := workspace.ReadFile(readmePath); err == nil && len(bytes.TrimSpace(existing)) > 0 {
+		updated, ok := replaceSection(string(existing), content)
+		if !ok {
+			workspace.Verbosef("README.md has no %s section, left alone\n", readmeBegin)
+			return nil
+		}
+		content = updated
+	}
+
+	if err := workspace.WriteFile(readmePath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write README.md: %v", err)
+	}
+
+	return nil
+}
+
+// replaceSection swaps the swan section of existing for the one in generated
+func replaceSection(existing, generated string) (string, bool) {
+	start, end := strings.Index(existing, readmeBegin), strings.Index(existing, readmeEnd)
+	if start < 0 || end < start {
+		return "", false
+	}
+
+	section := generated[strings.Index(generated, readmeBegin) : strings.Index(generated, readmeEnd)+len(readmeEnd)]
+	return existing[:start] + section + existing[end+len(readmeEnd):], true
+}
+
+// WriteEnvExample writes .env.example listing EnvVars, unless it was edited by
+// hand. an empty .env gets the same content
+func WriteEnvExample(projectPath string) error {
+	tmpl, err := template.New("env").Parse(envExampleTmpl)
+	if err != nil {
+		return fmt.Errorf("failed to parse .env.example template: %v", err)
+	}
+
+	vars, err := EnvVars(projectPath)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return fmt.Errorf("failed to execute .env.example template: %v", err)
+	}
+
+	examplePath := filepath.Join(projectPath, ".env.example")
+	if edited, _, err := genfile.Edited(examplePath); err == nil && edited {
+		workspace.Verbosef(".env.example was edited, left alone\n")
+	} else if err := genfile.WriteFile("env-example", examplePath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write .env.example: %v", err)
+	}
+
+	// .env holds local values, so it is only ever filled in once
+	envPath := filepath.Join(projectPath, ".env")
+	if existing, err := workspace.ReadFile(envPath); err == nil && len(existing) == 0 {
+		if err := workspace.WriteFile(envPath, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write .env: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -2,11 +2,12 @@ package project
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"text/template"
 
+	"github.com/rAlexander89/swan/genfile"
 	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
 )
 
 func WriteMain(projectPath string) error {
@@ -23,6 +24,7 @@ import (
     "log"
 
     "{{.ProjectName}}/internal/infrastructure/config"
+    "{{.ProjectName}}/internal/infrastructure/routes"
     "{{.ProjectName}}/internal/infrastructure/server"
 )
 
@@ -36,16 +38,21 @@ func main() {
 
     ctx := context.Background()
 
-    // initialize server (routes are registered during initialization)
+    // initialize server
     srv, err := server.NewServer(ctx, cfg)
     if err != nil {
         log.Fatalf("failed to initialize server: %v", err)
     }
 
+    // register the routes of every domain
+    if err := routes.RegisterRoutes(srv); err != nil {
+        log.Fatalf("failed to register routes: %v", err)
+    }
+
     fmt.Printf("starting application in %s mode...\n", env)
 
-    // start server (blocking)
-    if err := srv.Run("8080"); err != nil {
+    // start server on the address of configs/<env>.json (blocking)
+    if err := srv.Run(); err != nil {
         log.Fatalf("server error: %v", err)
     }
 }
@@ -60,7 +67,7 @@ func main() {
 	mainPath := filepath.Join(projectPath, "cmd", "main.go")
 
 	// ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(mainPath), 0755); err != nil {
+	if err := workspace.MkdirAll(filepath.Dir(mainPath), 0755); err != nil {
 		return fmt.Errorf("failed to create main directory: %v", err)
 	}
 
@@ -69,13 +76,7 @@ func main() {
 		return fmt.Errorf("failed to parse main template: %v", err)
 	}
 
-	f, err := os.Create(mainPath)
-	if err != nil {
-		return fmt.Errorf("failed to create main.go: %v", err)
-	}
-	defer f.Close()
-
-	if err := tmpl.Execute(f, data); err != nil {
+	if err := genfile.WriteTemplate("main", mainPath, tmpl, data); err != nil {
 		return fmt.Errorf("failed to write main template: %v", err)
 	}
 
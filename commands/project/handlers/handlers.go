@@ -2,12 +2,13 @@ package project
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/rAlexander89/swan/genfile"
 	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
 )
 
 type handlerTemplate struct {
@@ -47,7 +48,7 @@ import (
     "encoding/json"
     "net/http"
     
-    "{{.ProjectName}}/internal/core/domains/{{.DomainLower}}"
+    "{{.ProjectName}}/internal/core/domains/{{.DomainSnake}}"
     {{.DomainSnake}}_service "{{.ProjectName}}/internal/core/services/{{.DomainSnake}}_service"
     "{{.ProjectName}}/internal/infrastructure/server"
 )`,
@@ -141,13 +142,14 @@ func WriteHandler(projectPath, domain, ops string) error {
 		data.DomainSnake,
 	)
 
-	if err := os.MkdirAll(handlerDir, 0755); err != nil {
+	if err := workspace.MkdirAll(handlerDir, 0755); err != nil {
 		return fmt.Errorf("failed to create handler directory: %v", err)
 	}
 
 	tmpl := getHandlerTemplate(ops)
 
 	if err := writeTemplateToFile(
+		"handler",
 		filepath.Join(handlerDir, fmt.Sprintf("%s_handler.go", data.DomainLower)),
 		tmpl.handler,
 		data,
@@ -158,21 +160,11 @@ func WriteHandler(projectPath, domain, ops string) error {
 	return nil
 }
 
-func writeTemplateToFile(path, tmpl string, data templateData) error {
+func writeTemplateToFile(generator, path, tmpl string, data templateData) error {
 	t, err := template.New("").Parse(tmpl)
 	if err != nil {
 		return fmt.Errorf("failed to parse template: %v", err)
 	}
 
-	f, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %v", err)
-	}
-	defer f.Close()
-
-	if err := t.Execute(f, data); err != nil {
-		return fmt.Errorf("failed to execute template: %v", err)
-	}
-
-	return nil
+	return genfile.WriteTemplate(generator, path, t, data)
 }
@@ -2,143 +2,152 @@
 package project
 
 import (
-	_ "embed"
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 
-	project "github.com/rAlexander89/swan/commands/project/server"
+	"github.com/rAlexander89/swan/commands/project/app"
+	"github.com/rAlexander89/swan/manifest"
 	"github.com/rAlexander89/swan/nodes"
+	"github.com/rAlexander89/swan/workspace"
 )
 
 func init() {
 	nodes.RegisterCommand("new", New)
 }
 
-//go:embed new.json
-var projectStructure []byte
+// creates a new project directory and initializes a go module. the directory may be
+// relative to the working directory (or -C), absolute, or . for the current directory
+func New(args *nodes.Args) error {
+	dirName := args.String("directory")
 
-type FileStructure struct {
-	Type     string                   `json:"type"`
-	Children map[string]FileStructure `json:"children,omitempty"`
-}
-
-// creates a new project directory and initializes a go module
-func New(args []string) error {
-	if len(args) != 2 {
-		return fmt.Errorf("expected 2 arguments: directory name and project name")
+	// reject an unknown template or backend before anything is written
+	tmpl, err := LoadTemplate(args.String("template"))
+	if err != nil {
+		return err
 	}
 
-	dirName := args[0]
-	projectName := args[1]
+	// templates without the app module generate no database code
+	dbName := args.String("db")
+	if !tmpl.Runs("app") {
+		dbName = app.None
+	}
 
-	// get gopath
-	gopath := os.Getenv("GOPATH")
-	if gopath == "" {
-		return fmt.Errorf("GOPATH environment variable not set")
+	db, hasDB, err := app.LookupDatabase(dbName)
+	if err != nil {
+		return err
 	}
 
 	// create full project path
-	projectPath := filepath.Join(gopath, dirName)
+	projectPath := dirName
+	if !filepath.IsAbs(projectPath) {
+		projectPath = filepath.Join(workspace.Dir(), dirName)
+	}
+	projectPath = filepath.Clean(projectPath)
+
+	// module path defaults to the directory name, e.g. ./svc -> svc
+	projectName := args.String("module")
+	if projectName == "" {
+		projectName = filepath.Base(projectPath)
+	}
+
+	// an existing directory is only used when it is empty, e.g. swan new .
+	if err := checkEmpty(projectPath); err != nil {
+		return err
+	}
 
-	// check if directory already exists
-	if _, err := os.Stat(projectPath); !os.IsNotExist(err) {
-		return fmt.Errorf("directory already exists: %s", projectPath)
+	// a project inside another module would be swallowed by it
+	if modRoot, found := workspace.FindModuleRoot(filepath.Dir(projectPath)); found && !args.Bool("nested") {
+		return fmt.Errorf("%s is inside the module at %s, pass --nested to create a nested module anyway", projectPath, filepath.Join(modRoot, "go.mod"))
 	}
 
 	// create project directory
-	err := os.MkdirAll(projectPath, 0755)
+	err = workspace.MkdirAll(projectPath, 0755)
 	if err != nil {
 		return fmt.Errorf("failed to create directory: %v", err)
 	}
 
-	// change to project directory
-	err = os.Chdir(projectPath)
-	if err != nil {
-		return fmt.Errorf("failed to change directory: %v", err)
+	// point every generator at the new project directory
+	if err := workspace.SetDir(projectPath); err != nil {
+		return err
 	}
 
 	// initialize go module
-	cmd := exec.Command("go", "mod", "init", projectName)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to initialize go module: %v\noutput: %s", err, string(output))
+	if err := initModule(projectPath, projectName); err != nil {
+		return err
 	}
 
-	// scaffold project directories
-	err = ScaffoldDirs(projectPath)
-	if err != nil {
+	// scaffold the template's layout, then run its generators over it
+	data := TemplateData{Name: filepath.Base(projectPath), Module: projectName, DB: db, HasDB: hasDB}
+	if err := tmpl.Scaffold(projectPath, data); err != nil {
 		return fmt.Errorf("failed to scaffold project directories: %v", err)
 	}
 
-	// write config env.jsons and config structs
-	if err := WriteConfig(projectPath); err != nil {
-		return fmt.Errorf("failed to write config files: %v", err)
+	// record the project in swan.json before the generators run
+	if err := manifest.New(projectName, dbName, tmpl.Name).Save(); err != nil {
+		return err
 	}
 
-	if err := WriteConfigLoader(projectPath); err != nil {
-		return fmt.Errorf("failed to write config loader file: %v", err)
+	p := &newProject{path: projectPath, dbName: dbName, db: db, hasDB: hasDB, server: tmpl.Runs("server"), offline: args.Bool("offline")}
+	names := tmpl.Generators
+	if args.Bool("docker") && !tmpl.Runs("docker") {
+		names = append(names, "docker")
 	}
 
-	if err := WriteMain(projectPath); err != nil {
-		return fmt.Errorf("failed to write main.go: %v", err)
+	for _, name := range names {
+		if err := generators[name](p); err != nil {
+			return err
+		}
 	}
 
-	if err := WriteAppModule(projectPath); err != nil {
-		return fmt.Errorf("failed to write app.go: %v", err)
+	workspace.Printf("successfully created new project at %s\n", projectPath)
+	if len(p.pending) > 0 {
+		workspace.Printf("offline: pinned but not downloaded, run go mod tidy in %s once online:\n", projectPath)
+		for _, dep := range p.pending {
+			workspace.Printf("  %s\n", dep)
+		}
+		// the build would only report the missing modules
+		return nil
 	}
 
-	if err := project.WriteServer(projectPath); err != nil {
-		return fmt.Errorf("failed to write server.go: %v", err)
+	if args.Bool("no-verify") {
+		return nil
 	}
-
-	// before go mod init
-
-	fmt.Printf("successfully created new project at %s\n", projectPath)
-	return nil
+	return Verify(projectPath)
 }
 
-// ScaffoldDirs creates the directory structure and files based on embedded new.json
-func ScaffoldDirs(projectPath string) error {
-	var structure map[string]FileStructure
-	err := json.Unmarshal(projectStructure, &structure)
+// checkEmpty returns an error when path exists and is not an empty directory
+func checkEmpty(path string) error {
+	if !workspace.Exists(path) {
+		return nil
+	}
+
+	entries, err := os.ReadDir(path)
 	if err != nil {
-		return fmt.Errorf("failed to parse structure file: %v", err)
+		return fmt.Errorf("directory already exists: %s", path)
 	}
 
-	// create project structure
-	return createStructure(projectPath, structure)
+	if len(entries) > 0 {
+		return fmt.Errorf("directory already exists and is not empty: %s", path)
+	}
+
+	return nil
 }
 
-// createStructure recursively creates directories and files
-func createStructure(basePath string, structure map[string]FileStructure) error {
-	for name, item := range structure {
-		path := filepath.Join(basePath, name)
-
-		if item.Type == "directory" {
-			// create directory
-			err := os.MkdirAll(path, 0755)
-			if err != nil {
-				return fmt.Errorf("failed to create directory %s: %v", path, err)
-			}
-
-			// recursively create children if they exist
-			if item.Children != nil {
-				err = createStructure(path, item.Children)
-				if err != nil {
-					return err
-				}
-			}
-		} else if item.Type == "file" {
-			// create empty file
-			f, err := os.Create(path)
-			if err != nil {
-				return fmt.Errorf("failed to create file %s: %v", path, err)
-			}
-			f.Close()
+// initModule runs go mod init in projectPath. a dry run plans the go.mod
+// instead, so the generators that read the module name still work
+func initModule(projectPath, projectName string) error {
+	if workspace.DryRun() {
+		goMod := fmt.Sprintf("module %s\n", projectName)
+		if err := workspace.WriteFile(filepath.Join(projectPath, "go.mod"), []byte(goMod), 0644); err != nil {
+			return fmt.Errorf("failed to initialize go module: %v", err)
 		}
+		return nil
+	}
+
+	output, err := workspace.Command(projectPath, "go", "mod", "init", projectName)
+	if err != nil {
+		return fmt.Errorf("failed to initialize go module: %v\noutput: %s", err, string(output))
 	}
 
 	return nil
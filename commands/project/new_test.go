@@ -0,0 +1,154 @@
+// commands/project/new_test.go
+package project_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rAlexander89/swan/commands/domain"
+	"github.com/rAlexander89/swan/commands/project"
+	"github.com/rAlexander89/swan/commands/project/app"
+	"github.com/rAlexander89/swan/commands/project/db"
+	"github.com/rAlexander89/swan/commands/project/fly"
+	"github.com/rAlexander89/swan/nodes"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+// run calls a command the way main does, with its writes staged
+func run(t *testing.T, name string, fn func(*nodes.Args) error, values map[string][]string) {
+	t.Helper()
+
+	if err := workspace.Run(func() error { return fn(nodes.NewArgs(values)) }); err != nil {
+		t.Fatalf("swan %s: %v", name, err)
+	}
+}
+
+// needDriver skips the test unless the driver of backend is in the module
+// cache, so the project can be generated and built without the network
+func needDriver(t *testing.T, backend string) {
+	t.Helper()
+
+	db, _, err := app.LookupDatabase(backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !project.InModuleCache(db.Driver, db.Version) {
+		t.Skipf("%s@%s is not in the module cache", db.Driver, db.Version)
+	}
+}
+
+// goCheck runs go build and go vet in the project at dir with the module proxy
+// off. a module missing from the cache skips the test instead of failing it
+func goCheck(t *testing.T, dir string) {
+	t.Helper()
+
+	for _, args := range [][]string{{"build", "-o", t.TempDir() + string(filepath.Separator), "./..."}, {"vet", "./..."}} {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOPROXY=off", "GOFLAGS=-mod=mod")
+		out, err := cmd.CombinedOutput()
+		if err != nil && strings.Contains(string(out), "GOPROXY=off") {
+			t.Skipf("go %s needs a module that is not in the cache:\n%s", args[0], out)
+		}
+		if err != nil {
+			t.Fatalf("go %s: %v\n%s", args[0], err, out)
+		}
+	}
+}
+
+// quietly runs the test at the quiet level and restores the context after it
+func quietly(t *testing.T) {
+	t.Helper()
+
+	previous := workspace.Current()
+	workspace.Set(&workspace.Context{Level: workspace.Quiet})
+	t.Cleanup(func() { workspace.Set(previous) })
+}
+
+// TestGeneratedProjectsBuild generates a project with every generator for each
+// backend, adds domains with their repository, service, handler and routes, and
+// checks the result builds and vets
+func TestGeneratedProjectsBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generates and compiles whole projects")
+	}
+
+	for _, backend := range []string{"postgres", "mysql", "sqlite"} {
+		t.Run(backend, func(t *testing.T) {
+			needDriver(t, backend)
+			quietly(t)
+			dir := filepath.Join(t.TempDir(), backend)
+
+			run(t, "new", project.New, map[string][]string{
+				"directory": {dir},
+				"module":    {"example.com/" + backend},
+				"db":        {backend},
+				"template":  {project.DefaultTemplate},
+				"docker":    {"true"},
+				"offline":   {"true"},
+				"no-verify": {"true"},
+			})
+			run(t, "domain", domain.Create, map[string][]string{
+				"domain": {"BlogPost"},
+				"fields": {"Title", "string", "CreatedAt", "time.Time", "UpdatedAt", "time.Time"},
+				"tags":   {"json", "db"},
+			})
+			run(t, "domain", domain.Create, map[string][]string{
+				"domain": {"Tag"},
+			})
+
+			for _, name := range []string{"BlogPost", "Tag"} {
+				run(t, "hatch", db.Hatch, map[string][]string{
+					"domain":     {name},
+					"operations": {"C"},
+					"no-verify":  {"true"},
+				})
+				run(t, "fly", fly.Fly, map[string][]string{
+					"domain":    {name},
+					"no-verify": {"true"},
+				})
+			}
+
+			// routes.go wires every domain that flew
+			routes, err := os.ReadFile(filepath.Join(dir, "internal", "infrastructure", "routes", "routes.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, registered := range []string{"blogpostroutes.NewBlogPostRoutes", "tagroutes.NewTagRoutes"} {
+				if !strings.Contains(string(routes), registered) {
+					t.Errorf("routes.go does not call %s", registered)
+				}
+			}
+
+			goCheck(t, dir)
+		})
+	}
+}
+
+// TestGeneratedTemplatesBuild checks the project of each other template builds and vets
+func TestGeneratedTemplatesBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generates and compiles whole projects")
+	}
+
+	for _, template := range []string{"worker", "cli", "minimal"} {
+		t.Run(template, func(t *testing.T) {
+			needDriver(t, "postgres")
+			quietly(t)
+			dir := filepath.Join(t.TempDir(), template)
+
+			run(t, "new", project.New, map[string][]string{
+				"directory": {dir},
+				"db":        {"postgres"},
+				"template":  {template},
+				"offline":   {"true"},
+				"no-verify": {"true"},
+			})
+
+			goCheck(t, dir)
+		})
+	}
+}
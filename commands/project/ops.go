@@ -0,0 +1,50 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rAlexander89/swan/workspace"
+)
+
+// Ops are the CRUDI letters -c accepts
+const Ops = "CRUDI"
+
+// GeneratedOps are the CRUDI letters swan generates code for. the others are
+// accepted by -c but skipped until their generators exist
+const GeneratedOps = "C"
+
+// SplitOps splits the requested CRUDI letters into those swan generates and
+// those it skips
+func SplitOps(ops string) (generated, skipped string) {
+	for _, op := range strings.ToUpper(ops) {
+		if strings.ContainsRune(GeneratedOps, op) {
+			generated += string(op)
+		} else {
+			skipped += string(op)
+		}
+	}
+	return generated, skipped
+}
+
+// CheckOps validates the requested CRUDI letters, which must include Create,
+// and returns those swan generates. the skipped ones are printed
+func CheckOps(ops string) (string, error) {
+	ops = strings.ToUpper(ops)
+	for _, op := range ops {
+		if !strings.ContainsRune(Ops, op) {
+			return "", fmt.Errorf("invalid operation: %c", op)
+		}
+	}
+
+	// for now, only support Create, which the repository port always has
+	if !strings.ContainsRune(ops, 'C') {
+		return "", fmt.Errorf("currently only Create operation is supported")
+	}
+
+	generated, skipped := SplitOps(ops)
+	if skipped != "" {
+		workspace.Printf("operations %s are not generated yet, skipped\n", skipped)
+	}
+	return generated, nil
+}
@@ -0,0 +1,35 @@
+// commands/project/ops_test.go
+package project_test
+
+import (
+	"testing"
+
+	"github.com/rAlexander89/swan/commands/project"
+)
+
+func TestCheckOps(t *testing.T) {
+	quietly(t)
+
+	tests := []struct {
+		ops     string
+		want    string
+		wantErr bool
+	}{
+		{ops: "C", want: "C"},
+		{ops: "crudi", want: "C"},
+		{ops: "RC", want: "C"},
+		{ops: "RU", wantErr: true},
+		{ops: "CX", wantErr: true},
+		{ops: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := project.CheckOps(tt.ops)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckOps(%q) error = %v, want error %v", tt.ops, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("CheckOps(%q) = %q, want %q", tt.ops, got, tt.want)
+		}
+	}
+}
@@ -2,22 +2,18 @@ package port
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/rAlexander89/swan/genfile"
 	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
 )
 
 func GenerateRepositoryPort(domain string) error {
-	pwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %v", err)
-	}
-
-	repoDir := filepath.Join(pwd, "internal", "core", "ports", "repository")
-	if err := os.MkdirAll(repoDir, 0755); err != nil {
+	repoDir := workspace.Path("internal", "core", "ports", "repository")
+	if err := workspace.MkdirAll(repoDir, 0755); err != nil {
 		return fmt.Errorf("failed to create repository directory: %v", err)
 	}
 
@@ -30,10 +26,12 @@ func GenerateRepositoryPort(domain string) error {
 		Proj        string
 		Domain      string
 		LowerDomain string
+		SnakeDomain string
 	}{
 		Proj:        projName,
 		Domain:      utils.ToUpperFirst(domain),
 		LowerDomain: strings.ToLower(domain),
+		SnakeDomain: utils.PascalToSnake(domain),
 	}
 
 	tmpl := template.Must(template.New("repository").Parse(`package repository
@@ -42,7 +40,7 @@ import (
     "context"
     "errors"
     
-    "{{.Proj}}/internal/core/domains/{{.LowerDomain}}"
+    "{{.Proj}}/internal/core/domains/{{.SnakeDomain}}"
 )
 
 var (
@@ -55,13 +53,7 @@ type {{.Domain}}Repository interface {
 
 	filePath := filepath.Join(repoDir, fmt.Sprintf("%s_repository.go", utils.PascalToSnake(domain)))
 
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create repository file: %v", err)
-	}
-	defer file.Close()
-
-	if err := tmpl.Execute(file, data); err != nil {
+	if err := genfile.WriteTemplate("repository-port", filePath, tmpl, data); err != nil {
 		return fmt.Errorf("failed to write repository template: %v", err)
 	}
 
@@ -2,12 +2,13 @@ package project
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/rAlexander89/swan/genfile"
 	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
 )
 
 const (
@@ -38,7 +39,7 @@ func WriteRoutes(projectPath, domain, ops string) error {
 		ProjectName: projectName,
 		DomainTitle: utils.ToUpperFirst(domain),
 		DomainLower: strings.ToLower(domain),
-		DomainSnake: utils.ToSnakeCase(domain),
+		DomainSnake: utils.PascalToSnake(domain),
 		DomainKebab: utils.PascalToKebab(domain),
 		Operations:  ops,
 	}
@@ -52,7 +53,7 @@ func WriteRoutes(projectPath, domain, ops string) error {
 		"routes",
 		data.DomainSnake,
 	)
-	if err := os.MkdirAll(routesDir, 0755); err != nil {
+	if err := workspace.MkdirAll(routesDir, 0755); err != nil {
 		return fmt.Errorf("failed to create routes directory: %v", err)
 	}
 
@@ -61,7 +62,7 @@ func WriteRoutes(projectPath, domain, ops string) error {
 
 	tmpl := getRoutesTemplate()
 
-	return writeTemplateToFile(routesPath, tmpl, data)
+	return writeTemplateToFile("routes", routesPath, tmpl, data)
 }
 
 func getRoutesTemplate() string {
@@ -89,7 +90,7 @@ func (r *{{.DomainTitle}}Routes) RegisterRoutes(group *server.RouteGroup) {
 }`
 }
 
-func writeTemplateToFile(path, tmpl string, data routeData) error {
+func writeTemplateToFile(generator, path, tmpl string, data routeData) error {
 	funcMap := template.FuncMap{
 		"hasOperation": func(ops, op string) bool {
 			return strings.ContainsAny(ops, op)
@@ -99,10 +100,5 @@ func writeTemplateToFile(path, tmpl string, data routeData) error {
 	if err != nil {
 		return fmt.Errorf("failed to parse template: %v", err)
 	}
-	f, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %v", err)
-	}
-	defer f.Close()
-	return t.Execute(f, data)
+	return genfile.WriteTemplate(generator, path, t, data)
 }
@@ -2,16 +2,68 @@ package project
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/rAlexander89/swan/commands/project/app"
+	"github.com/rAlexander89/swan/genfile"
 	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
 )
 
-func WriteTopLevelRoutes(projectPath, domain, ops string) error {
-	// get project name for imports
+// DomainRoutes is a domain whose routes are registered in routes.go
+type DomainRoutes struct {
+	Title string
+	Lower string
+	Snake string
+}
+
+// NewDomainRoutes returns the names routes.go uses for domain
+func NewDomainRoutes(domain string) DomainRoutes {
+	return DomainRoutes{
+		Title: utils.ToUpperFirst(domain),
+		Lower: strings.ToLower(domain),
+		Snake: utils.PascalToSnake(domain),
+	}
+}
+
+const topLevelRoutesTmpl = `package routes
+
+import (
+{{- range .Domains}}
+    {{.Lower}}handler "{{$.ProjectName}}/internal/infrastructure/http/handlers/{{.Snake}}"
+    {{.Lower}}repository "{{$.ProjectName}}/internal/app/repositories/{{$.DB.Name}}/domains/{{.Snake}}"
+    {{.Lower}}routes "{{$.ProjectName}}/internal/infrastructure/server/routes/{{.Snake}}"
+    {{.Lower}}service "{{$.ProjectName}}/internal/core/services/{{.Snake}}_service"
+{{- end}}
+    "{{.ProjectName}}/internal/infrastructure/server"
+)
+
+// RegisterRoutes wires every domain's repository, service and handler together
+// and registers its routes under /api/v1
+func RegisterRoutes(s *server.Server) error {
+    v1 := s.Group("/api").Group("/v1")
+{{- if .Domains}}
+    a := s.App()
+{{range .Domains}}
+    // register {{.Lower}} routes
+    {{.Lower}}Repository := {{.Lower}}repository.New(a.{{$.DB.Title}}DB())
+    {{.Lower}}Handler := {{.Lower}}handler.New{{.Title}}Handler({{.Lower}}service.New({{.Lower}}Repository))
+    {{.Lower}}routes.New{{.Title}}Routes({{.Lower}}Handler).RegisterRoutes(v1)
+{{end}}
+{{- else}}
+
+    // swan fly <Domain> registers the routes of a domain here
+    _ = v1
+{{- end}}
+    return nil
+}
+`
+
+// WriteTopLevelRoutes writes routes.go registering the routes of domains. a
+// routes.go edited by hand is left alone and the code to add is printed instead
+func WriteTopLevelRoutes(projectPath string, db app.Database, domains []DomainRoutes) error {
 	projectName, err := utils.GetProjectName()
 	if err != nil {
 		return fmt.Errorf("failed to get project name: %w", err)
@@ -19,53 +71,33 @@ func WriteTopLevelRoutes(projectPath, domain, ops string) error {
 
 	data := struct {
 		ProjectName string
-		DomainTitle string
-		DomainLower string
+		DB          app.Database
+		Domains     []DomainRoutes
 	}{
 		ProjectName: projectName,
-		DomainTitle: utils.ToUpperFirst(domain),
-		DomainLower: strings.ToLower(domain),
+		DB:          db,
+		Domains:     domains,
 	}
 
 	// ensure routes directory exists
 	routesDir := filepath.Join(projectPath, "internal", "infrastructure", "routes")
-	if err := os.MkdirAll(routesDir, 0755); err != nil {
+	if err := workspace.MkdirAll(routesDir, 0755); err != nil {
 		return fmt.Errorf("failed to create routes directory: %v", err)
 	}
 
-	// create routes.go file
+	tmpl := template.Must(template.New("routes").Parse(topLevelRoutesTmpl))
 	routesPath := filepath.Join(routesDir, "routes.go")
-	if _, err := os.Stat(routesPath); os.IsNotExist(err) {
-		// routes.go doesn't exist, create it
-		tmpl := template.Must(template.New("routes").Parse(`package routes
-
-import (
-    "{{.ProjectName}}/internal/app/handlers/api/{{.DomainLower}}s"
-    "{{.ProjectName}}/internal/infrastructure/server"
-    "{{.ProjectName}}/internal/infrastructure/routes/{{.DomainLower}}"
-)
 
-func RegisterRoutes(s *server.Server, {{.DomainLower}}Handler *{{.DomainLower}}s.{{.DomainTitle}}Handler) {
-    apiGroup := s.Group("/api")
-    v1Group := apiGroup.Group("/v1")
-
-    // register {{.DomainLower}} routes
-    {{.DomainLower}}Routes := {{.DomainLower}}.New{{.DomainTitle}}Routes({{.DomainLower}}Handler)
-    {{.DomainLower}}Routes.RegisterRoutes(v1Group)
-
-    // register other domain routes as needed
-    // 
-}`))
-
-		f, err := os.Create(routesPath)
-		if err != nil {
-			return fmt.Errorf("failed to create routes.go file: %v", err)
+	if edited, _, err := genfile.Edited(routesPath); err == nil && edited {
+		workspace.Printf("%s was edited, register the routes yourself:\n", workspace.Rel(routesPath))
+		for _, d := range domains {
+			workspace.Printf("  %sroutes.New%sRoutes(handler).RegisterRoutes(v1)\n", d.Lower, d.Title)
 		}
-		defer f.Close()
+		return nil
+	}
 
-		if err := tmpl.Execute(f, data); err != nil {
-			return fmt.Errorf("failed to execute routes.go template: %v", err)
-		}
+	if err := genfile.WriteTemplate("api-routes", routesPath, tmpl, data); err != nil {
+		return fmt.Errorf("failed to execute routes.go template: %v", err)
 	}
 
 	return nil
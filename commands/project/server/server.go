@@ -3,10 +3,11 @@ package project
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 
+	"github.com/rAlexander89/swan/genfile"
 	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
 )
 
 func WriteServer(projectPath string) error {
@@ -22,16 +23,17 @@ import (
     "errors"
     "fmt"
     "log"
+    "net"
     "net/http"
     "os"
     "os/signal"
+    "strconv"
     "sync"
     "syscall"
     "time"
     
     "%s/internal/app"
     "%s/internal/infrastructure/config"
-    "%s/internal/infrastructure/routes/routes.go"
 )
 
 type ServiceRegistrar interface {
@@ -43,12 +45,13 @@ type RouteRegistrar interface {
 }
 
 type Server struct {
-    srv         *http.Server
-    mux         *http.ServeMux
-    app         *app.App
-    wg          sync.WaitGroup
-    middleware  []Middleware
-    routeGroups map[string]*RouteGroup
+    srv           *http.Server
+    mux           *http.ServeMux
+    app           *app.App
+    wg            sync.WaitGroup
+    middleware    []Middleware
+    routeGroups   map[string]*RouteGroup
+    shutdownGrace time.Duration
 }
 
 type Middleware func(http.HandlerFunc) http.HandlerFunc
@@ -59,26 +62,40 @@ type RouteGroup struct {
     middleware []Middleware
 }
 
+// NewServer returns a server for the application, set up by the server
+// section of cfg. a zero timeout is none
 func NewServer(ctx context.Context, cfg *config.Config) (*Server, error) {
     application, err := app.NewApp(ctx, cfg)
     if err != nil {
         return nil, fmt.Errorf("failed to initialize application: %%w", err)
     }
 
+    settings := cfg.Server
     srv := &Server{
-        mux:         http.NewServeMux(),
-        app:         application,
-        middleware:  make([]Middleware, 0),
-        routeGroups: make(map[string]*RouteGroup),
+        mux:           http.NewServeMux(),
+        app:           application,
+        middleware:    make([]Middleware, 0),
+        routeGroups:   make(map[string]*RouteGroup),
+        shutdownGrace: time.Duration(settings.ShutdownGrace) * time.Second,
     }
 
-    if err := routes.RegisterRoutes(srv); err != nil {
-        return nil, fmt.Errorf("failed to register routes: %%w", err)
+    srv.srv = &http.Server{
+        Addr:           net.JoinHostPort(settings.Host, strconv.Itoa(settings.Port)),
+        Handler:        srv.mux,
+        ReadTimeout:    time.Duration(settings.ReadTimeout) * time.Second,
+        WriteTimeout:   time.Duration(settings.WriteTimeout) * time.Second,
+        IdleTimeout:    time.Duration(settings.IdleTimeout) * time.Second,
+        MaxHeaderBytes: settings.MaxHeaderBytes,
     }
 
     return srv, nil
 }
 
+// App returns the application the server's routes are wired to
+func (s *Server) App() *app.App {
+    return s.app
+}
+
 func (s *Server) RegisterServices(registrar ServiceRegistrar) error {
     if err := registrar.RegisterServices(s.app); err != nil {
         return fmt.Errorf("failed to register services: %%w", err)
@@ -157,12 +174,9 @@ func (g *RouteGroup) DELETE(path string, handler http.HandlerFunc) {
     g.Handle(http.MethodDelete, path, handler)
 }
 
-func (s *Server) Run(port string) error {
-    s.srv = &http.Server{
-        Addr:    fmt.Sprintf(":%%s", port),
-        Handler: s.mux,
-    }
-
+// Run serves until the process is signalled, then gives requests in flight
+// the shutdown grace of the config to finish
+func (s *Server) Run() error {
     serverCtx, serverStopCtx := context.WithCancel(context.Background())
 
     sig := make(chan os.Signal, 1)
@@ -173,7 +187,7 @@ func (s *Server) Run(port string) error {
         defer s.wg.Done()
         <-sig
 
-        shutdownCtx, cancel := context.WithTimeout(serverCtx, 30*time.Second)
+        shutdownCtx, cancel := context.WithTimeout(serverCtx, s.shutdownGrace)
         defer cancel()
 
         go func() {
@@ -189,7 +203,7 @@ func (s *Server) Run(port string) error {
         serverStopCtx()
     }()
 
-    log.Printf("server starting on port %%s", port)
+    log.Printf("server listening on %%s", s.srv.Addr)
     if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
         return fmt.Errorf("error starting server: %%w", err)
     }
@@ -208,15 +222,15 @@ func (s *Server) shutdown(ctx context.Context) error {
     }
 
     return nil
-}`, projectName, projectName, projectName)
+}`, projectName, projectName)
 
 	serverDir := filepath.Join(projectPath, "internal", "infrastructure", "server")
-	if err := os.MkdirAll(serverDir, 0755); err != nil {
+	if err := workspace.MkdirAll(serverDir, 0755); err != nil {
 		return fmt.Errorf("failed to create server directory: %v", err)
 	}
 
 	serverPath := filepath.Join(serverDir, "server.go")
-	if err := os.WriteFile(serverPath, []byte(serverContent), 0644); err != nil {
+	if err := genfile.WriteFile("server", serverPath, []byte(serverContent), 0644); err != nil {
 		return fmt.Errorf("failed to write server.go: %v", err)
 	}
 
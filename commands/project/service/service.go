@@ -3,12 +3,13 @@ package service
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/rAlexander89/swan/genfile"
 	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
 )
 
 const (
@@ -39,20 +40,15 @@ func getOperations(ops string) []operation {
 }
 
 func GenerateService(domain, ops string) error {
-	pwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %v", err)
-	}
-
 	projectName, err := utils.GetProjectName()
 	if err != nil {
 		return err
 	}
 
 	domainSnake := utils.PascalToSnake(domain)
-	serviceDir := filepath.Join(pwd, "internal", "core", "services", fmt.Sprintf("%s_service", domainSnake))
+	serviceDir := workspace.Path("internal", "core", "services", fmt.Sprintf("%s_service", domainSnake))
 
-	if err := os.MkdirAll(serviceDir, 0755); err != nil {
+	if err := workspace.MkdirAll(serviceDir, 0755); err != nil {
 		return fmt.Errorf("failed to create service directory: %v", err)
 	}
 
@@ -90,7 +86,7 @@ import (
     "context"
     "errors"
     
-    "{{.ProjectName}}/internal/core/domains/{{.DomainLower}}"
+    "{{.ProjectName}}/internal/core/domains/{{.DomainSnake}}"
 )
 
 var (
@@ -108,22 +104,18 @@ type Service interface {
 		ProjectName string
 		DomainUpper string
 		DomainLower string
+		DomainSnake string
 		Functions   []string
 	}{
 		Package:     fmt.Sprintf("%s_service", lowerDomain),
 		ProjectName: projectName,
 		DomainUpper: upperDomain,
 		DomainLower: lowerDomain,
+		DomainSnake: utils.PascalToSnake(domain),
 		Functions:   functions,
 	}
 
-	typesFile, err := os.Create(filepath.Join(serviceDir, "types.go"))
-	if err != nil {
-		return err
-	}
-	defer typesFile.Close()
-
-	return tmpl.Execute(typesFile, data)
+	return genfile.WriteTemplate("service-types", filepath.Join(serviceDir, "types.go"), tmpl, data)
 }
 
 func generateImplementation(domain, ops, serviceDir, projectName string) error {
@@ -136,7 +128,7 @@ import (
     "context"
     "fmt"
 
-    "{{.ProjectName}}/internal/core/domains/{{.DomainLower}}"
+    "{{.ProjectName}}/internal/core/domains/{{.DomainSnake}}"
     "{{.ProjectName}}/internal/core/ports/repository"
 )
 
@@ -168,20 +160,16 @@ func (s *service) Create{{$.DomainUpper}}(ctx context.Context, {{$.DomainLower}}
 		ProjectName string
 		DomainUpper string
 		DomainLower string
+		DomainSnake string
 		Operations  []operation
 	}{
 		Package:     fmt.Sprintf("%s_service", lowerDomain),
 		ProjectName: projectName,
 		DomainUpper: upperDomain,
 		DomainLower: lowerDomain,
+		DomainSnake: utils.PascalToSnake(domain),
 		Operations:  getOperations(ops),
 	}
 
-	implFile, err := os.Create(filepath.Join(serviceDir, fmt.Sprintf("%s.go", lowerDomain)))
-	if err != nil {
-		return err
-	}
-	defer implFile.Close()
-
-	return tmpl.Execute(implFile, data)
+	return genfile.WriteTemplate("service", filepath.Join(serviceDir, fmt.Sprintf("%s.go", lowerDomain)), tmpl, data)
 }
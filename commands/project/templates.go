@@ -0,0 +1,297 @@
+package project
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/rAlexander89/swan/commands/project/app"
+	routes "github.com/rAlexander89/swan/commands/project/routes"
+	project "github.com/rAlexander89/swan/commands/project/server"
+	"github.com/rAlexander89/swan/genfile"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+// DefaultTemplate is the preset swan new uses without --template
+const DefaultTemplate = "api"
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+// Template is a preset for swan new: a directory layout and the generators run over it.
+// presets live in templates/<name>/template.json, built in or under ~/.swan/templates
+type Template struct {
+	Name        string                   `json:"-"`
+	Description string                   `json:"description"`
+	Generators  []string                 `json:"generators"`
+	Structure   map[string]FileStructure `json:"structure"`
+
+	// files holds the template.json and the sources its file entries point to
+	files fs.FS
+}
+
+// FileStructure is a directory or file entry of a template layout. a file is
+// created empty unless it has content, or a source file next to template.json.
+// both are text/templates executed with TemplateData
+type FileStructure struct {
+	Type     string                   `json:"type"`
+	Content  string                   `json:"content,omitempty"`
+	Source   string                   `json:"source,omitempty"`
+	Children map[string]FileStructure `json:"children,omitempty"`
+}
+
+// TemplateData is passed to the content of template files
+type TemplateData struct {
+	Name   string // base name of the project directory
+	Module string
+	DB     app.Database
+	HasDB  bool
+}
+
+// newProject is the state the generators of swan new share
+type newProject struct {
+	path    string
+	dbName  string
+	db      app.Database
+	hasDB   bool
+	server  bool // the template runs the server generator
+	offline bool
+	pending []string // drivers pinned offline that still have to be downloaded
+}
+
+// generators are the steps a template can list, keyed by name
+var generators = map[string]func(p *newProject) error{
+	"config": func(p *newProject) error {
+		if err := WriteConfig(p.path, p.dbName, p.server); err != nil {
+			return fmt.Errorf("failed to write config files: %v", err)
+		}
+		if err := WriteConfigLoader(p.path); err != nil {
+			return fmt.Errorf("failed to write config loader file: %v", err)
+		}
+		return nil
+	},
+	"main": func(p *newProject) error {
+		if err := WriteMain(p.path); err != nil {
+			return fmt.Errorf("failed to write main.go: %v", err)
+		}
+		return nil
+	},
+	"app": func(p *newProject) error {
+		if err := WriteAppModule(p.path, p.dbName); err != nil {
+			return fmt.Errorf("failed to write app.go: %v", err)
+		}
+		if !p.hasDB {
+			return nil
+		}
+
+		fetchLater, err := getDriver(p.path, p.db, p.offline)
+		if err != nil {
+			return err
+		}
+		if fetchLater {
+			p.pending = append(p.pending, p.db.Driver+"@"+p.db.Version)
+		}
+		return nil
+	},
+	"docs": func(p *newProject) error {
+		if err := WriteDocs(p.path); err != nil {
+			return fmt.Errorf("failed to write project docs: %v", err)
+		}
+		return nil
+	},
+	"docker": func(p *newProject) error {
+		if err := WriteContainerFiles(p.path, p.dbName); err != nil {
+			return fmt.Errorf("failed to write container files: %v", err)
+		}
+		return nil
+	},
+	"server": func(p *newProject) error {
+		if err := project.WriteServer(p.path); err != nil {
+			return fmt.Errorf("failed to write server.go: %v", err)
+		}
+		// routes.go starts empty, swan fly registers each domain in it
+		if err := routes.WriteTopLevelRoutes(p.path, p.db, nil); err != nil {
+			return fmt.Errorf("failed to write routes.go: %v", err)
+		}
+		return nil
+	},
+}
+
+// TemplatesDir returns the directory of user presets, ~/.swan/templates
+func TemplatesDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %v", err)
+	}
+	return filepath.Join(home, ".swan", "templates"), nil
+}
+
+// LoadTemplate returns the preset called name. a user preset wins over a built in one
+func LoadTemplate(name string) (*Template, error) {
+	if dir, err := TemplatesDir(); err == nil {
+		userDir := filepath.Join(dir, name)
+		if _, err := os.Stat(filepath.Join(userDir, "template.json")); err == nil {
+			return parseTemplate(name, os.DirFS(userDir))
+		}
+	}
+
+	builtin, err := fs.Sub(builtinTemplates, "templates/"+name)
+	if err != nil || !isTemplate(builtin) {
+		return nil, fmt.Errorf("unknown template %s, expected one of: %s", name, strings.Join(TemplateNames(), ", "))
+	}
+
+	return parseTemplate(name, builtin)
+}
+
+// TemplateNames returns the names of the built in and user presets
+func TemplateNames() []string {
+	seen := make(map[string]bool)
+
+	entries, _ := builtinTemplates.ReadDir("templates")
+	for _, entry := range entries {
+		if entry.IsDir() {
+			seen[entry.Name()] = true
+		}
+	}
+
+	if dir, err := TemplatesDir(); err == nil {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if entry.IsDir() && isTemplate(os.DirFS(filepath.Join(dir, entry.Name()))) {
+				seen[entry.Name()] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func isTemplate(files fs.FS) bool {
+	_, err := fs.Stat(files, "template.json")
+	return err == nil
+}
+
+func parseTemplate(name string, files fs.FS) (*Template, error) {
+	data, err := fs.ReadFile(files, "template.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %v", name, err)
+	}
+
+	t := &Template{Name: name, files: files}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %v", name, err)
+	}
+
+	for _, g := range t.Generators {
+		if _, exists := generators[g]; !exists {
+			return nil, fmt.Errorf("template %s: unknown generator %s", name, g)
+		}
+	}
+
+	return t, nil
+}
+
+// Runs reports whether the template lists the generator
+func (t *Template) Runs(generator string) bool {
+	for _, g := range t.Generators {
+		if g == generator {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDir reports whether the template's layout has the directory rel
+func (t *Template) HasDir(rel string) bool {
+	structure := t.Structure
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i, part := range parts {
+		item, exists := structure[part]
+		if !exists || item.Type != "directory" {
+			return false
+		}
+		if i == len(parts)-1 {
+			return true
+		}
+		structure = item.Children
+	}
+	return false
+}
+
+// Scaffold creates the template's layout under projectPath
+func (t *Template) Scaffold(projectPath string, data TemplateData) error {
+	return t.createStructure(projectPath, t.Structure, data)
+}
+
+// createStructure recursively creates directories and files
+func (t *Template) createStructure(basePath string, structure map[string]FileStructure, data TemplateData) error {
+	for name, item := range structure {
+		path := filepath.Join(basePath, name)
+
+		switch item.Type {
+		case "directory":
+			// create directory
+			if err := workspace.MkdirAll(path, 0755); err != nil {
+				return fmt.Errorf("failed to create directory %s: %v", path, err)
+			}
+
+			// recursively create children if they exist
+			if item.Children != nil {
+				if err := t.createStructure(path, item.Children, data); err != nil {
+					return err
+				}
+			}
+		case "file":
+			if err := t.createFile(path, item, data); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("template %s: %s has unknown type %q", t.Name, name, item.Type)
+		}
+	}
+
+	return nil
+}
+
+func (t *Template) createFile(path string, item FileStructure, data TemplateData) error {
+	content := item.Content
+	if item.Source != "" {
+		source, err := fs.ReadFile(t.files, item.Source)
+		if err != nil {
+			return fmt.Errorf("template %s: failed to read %s: %v", t.Name, item.Source, err)
+		}
+		content = string(source)
+	}
+
+	// create empty file
+	if content == "" {
+		f, err := workspace.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create file %s: %v", path, err)
+		}
+		return f.Close()
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Parse(content)
+	if err != nil {
+		return fmt.Errorf("template %s: failed to parse %s: %v", t.Name, path, err)
+	}
+
+	if err := genfile.WriteTemplate("template-"+t.Name, path, tmpl, data); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rAlexander89/swan/genfile"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+// diagnosticLine matches the file:line:col: message lines of go build and go vet
+var diagnosticLine = regexp.MustCompile(`^(?:vet: )?(\S+\.go):(\d+)(?::\d+)?: (.+)$`)
+
+// diagnostic is a compiler or vet error in a file of the project
+type diagnostic struct {
+	file      string // relative to the project
+	line      string
+	message   string
+	generator string // the generator that wrote the file, empty when swan didn't
+}
+
+// Verify runs go build and go vet on the project. the problems they find are
+// printed on stderr, grouped by the generator that wrote each file, whatever
+// the output level. the returned error is marked by workspace.KeepChanges: the
+// generated code stays in place to be fixed by hand, and swan exits non-zero
+func Verify(projectPath string) error {
+	if workspace.DryRun() {
+		return nil
+	}
+
+	workspace.Printf("verifying the project builds\n")
+
+	// binaries go to a scratch directory: go build writes one when ./... is a
+	// single main package, e.g. the minimal template
+	binDir, err := os.MkdirTemp("", "swan-verify-")
+	if err != nil {
+		return workspace.KeepChanges(fmt.Errorf("failed to verify the project: %v", err))
+	}
+	defer os.RemoveAll(binDir)
+
+	for _, step := range [][]string{{"build", "-o", binDir + string(filepath.Separator), "./..."}, {"vet", "./..."}} {
+		out, err := workspace.Command(projectPath, "go", step...)
+		if err == nil {
+			continue
+		}
+
+		diagnostics := parseDiagnostics(projectPath, out)
+		if len(diagnostics) == 0 {
+			// nothing to map back, e.g. a missing module: show go's own output
+			fmt.Fprintf(os.Stderr, "%s", out)
+			return workspace.KeepChanges(fmt.Errorf("go %s failed: %v, the files were left in place", step[0], err))
+		}
+
+		reportDiagnostics(diagnostics)
+		// vet repeats the type errors of a failed build
+		return workspace.KeepChanges(fmt.Errorf("go %s reported %d problem(s), the files were left in place", step[0], len(diagnostics)))
+	}
+
+	workspace.Printf("go build and go vet passed\n")
+	return nil
+}
+
+// parseDiagnostics reads the errors in go's output and looks up the generator
+// of each file from its swan header
+func parseDiagnostics(projectPath string, out []byte) []diagnostic {
+	var diagnostics []diagnostic
+	generators := make(map[string]string)
+
+	for _, text := range strings.Split(string(out), "\n") {
+		match := diagnosticLine.FindStringSubmatch(strings.TrimSpace(text))
+		if match == nil {
+			continue
+		}
+
+		path := match[1]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectPath, path)
+		}
+
+		generator, seen := generators[path]
+		if !seen {
+			if data, err := workspace.ReadFile(path); err == nil {
+				if header, _, ok := genfile.Parse(path, data); ok {
+					generator = header.Generator
+				}
+			}
+			generators[path] = generator
+		}
+
+		rel, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			rel = path
+		}
+
+		diagnostics = append(diagnostics, diagnostic{
+			file:      rel,
+			line:      match[2],
+			message:   match[3],
+			generator: generator,
+		})
+	}
+
+	return diagnostics
+}
+
+// reportDiagnostics prints the errors on stderr grouped by generator, files
+// swan didn't write last
+func reportDiagnostics(diagnostics []diagnostic) {
+	byGenerator := make(map[string][]diagnostic)
+	for _, d := range diagnostics {
+		byGenerator[d.generator] = append(byGenerator[d.generator], d)
+	}
+
+	generators := make([]string, 0, len(byGenerator))
+	for generator := range byGenerator {
+		if generator != "" {
+			generators = append(generators, generator)
+		}
+	}
+	sort.Strings(generators)
+	if _, exists := byGenerator[""]; exists {
+		generators = append(generators, "")
+	}
+
+	for _, generator := range generators {
+		if generator == "" {
+			fmt.Fprintf(os.Stderr, "not generated by swan:\n")
+		} else {
+			fmt.Fprintf(os.Stderr, "%s generator:\n", generator)
+		}
+
+		for _, d := range byGenerator[generator] {
+			fmt.Fprintf(os.Stderr, "  %s:%s: %s\n", d.file, d.line, d.message)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// commands/version/version.go
+package version
+
+import (
+	"fmt"
+
+	"github.com/rAlexander89/swan/nodes"
+	"github.com/rAlexander89/swan/version"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+func init() {
+	nodes.RegisterCommand("version", Version)
+}
+
+// Version prints the version of the running swan build
+func Version(*nodes.Args) error {
+	info := version.Read()
+	fmt.Printf("swan %s\n", info)
+
+	// -v prints the full build info
+	if workspace.Current().Level >= workspace.Verbose {
+		if info.Revision != "" {
+			fmt.Printf("revision: %s\n", info.Revision)
+			fmt.Printf("modified: %t\n", info.Modified)
+		}
+		if info.GoVersion != "" {
+			fmt.Printf("go:       %s\n", info.GoVersion)
+		}
+	}
+
+	return nil
+}
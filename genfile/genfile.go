@@ -0,0 +1,158 @@
+// genfile/genfile.go
+//
+// Package genfile writes generated files with a standard header naming the
+// generator, the swan version and a hash of the generated content:
+//
+//	// generated by swan devel+1a2b3c4d5e6f (server)
+//	// swan:hash 9f86d081884c7d65
+//
+// the hash covers everything below the header, so an edited file can be told
+// apart from one that is still exactly what swan wrote.
+package genfile
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/rAlexander89/swan/version"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+const (
+	generatedPrefix = "generated by swan "
+	hashPrefix      = "swan:hash "
+)
+
+// Header is the parsed header of a generated file
+type Header struct {
+	Generator string
+	Version   string
+	Hash      string
+}
+
+// WriteFile writes content to path behind the standard header. files without
+// a comment syntax, e.g. json, are written as they are
+func WriteFile(generator, path string, content []byte, perm fs.FileMode) error {
+	return workspace.WriteFile(path, Stamp(generator, path, content), perm)
+}
+
+// WriteTemplate executes tmpl with data and writes the result like WriteFile
+func WriteTemplate(generator, path string, tmpl *template.Template, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template: %v", err)
+	}
+
+	return WriteFile(generator, path, buf.Bytes(), 0644)
+}
+
+// Stamp returns content with the standard header for path prepended
+func Stamp(generator, path string, content []byte) []byte {
+	comment, ok := commentFor(path)
+	if !ok {
+		return content
+	}
+
+	// leading blank lines would separate the header from the code it describes
+	body := bytes.TrimLeft(content, "\n")
+
+	// go code is written gofmt'ed. code that doesn't parse is written as it is,
+	// so the build reports the error against the generated file
+	if filepath.Ext(path) == ".go" {
+		if formatted, err := format.Source(body); err == nil {
+			body = formatted
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s%s%s (%s)\n", comment, generatedPrefix, version.Version(), generator)
+	fmt.Fprintf(&b, "%s%s%s\n", comment, hashPrefix, Hash(body))
+
+	// go files need a blank line so the header doesn't become the package doc
+	if filepath.Ext(path) == ".go" {
+		b.WriteString("\n")
+	}
+	b.Write(body)
+
+	return b.Bytes()
+}
+
+// Hash returns the content hash used in the header
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Parse splits a generated file into its header and body. ok is false when the
+// file has no swan header
+func Parse(path string, data []byte) (header Header, body []byte, ok bool) {
+	comment, supported := commentFor(path)
+	if !supported {
+		return Header{}, nil, false
+	}
+
+	first, rest, found := bytes.Cut(data, []byte("\n"))
+	if !found || !strings.HasPrefix(string(first), comment+generatedPrefix) {
+		return Header{}, nil, false
+	}
+
+	second, rest, found := bytes.Cut(rest, []byte("\n"))
+	if !found || !strings.HasPrefix(string(second), comment+hashPrefix) {
+		return Header{}, nil, false
+	}
+
+	// "<version> (<generator>)"
+	stamp := strings.TrimPrefix(string(first), comment+generatedPrefix)
+	v, generator, _ := strings.Cut(stamp, " ")
+	header = Header{
+		Generator: strings.Trim(generator, "()"),
+		Version:   v,
+		Hash:      strings.TrimPrefix(string(second), comment+hashPrefix),
+	}
+
+	if filepath.Ext(path) == ".go" {
+		rest = bytes.TrimPrefix(rest, []byte("\n"))
+	}
+
+	return header, rest, true
+}
+
+// Edited reports whether the generated file at path was changed since swan wrote it.
+// files without a swan header are reported as not generated
+func Edited(path string) (edited bool, generated bool, err error) {
+	data, err := workspace.ReadFile(path)
+	if err != nil {
+		return false, false, err
+	}
+
+	header, body, ok := Parse(path, data)
+	if !ok {
+		return false, false, nil
+	}
+
+	return Hash(body) != header.Hash, true, nil
+}
+
+// commentFor returns the line comment marker for the file type of path
+func commentFor(path string) (string, bool) {
+	switch filepath.Ext(path) {
+	case ".go":
+		return "// ", true
+	case ".yml", ".yaml", ".env", ".sh", ".toml":
+		return "# ", true
+	}
+
+	switch filepath.Base(path) {
+	case "Makefile", "Dockerfile", ".env", ".env.example", ".gitignore":
+		return "# ", true
+	}
+
+	return "", false
+}
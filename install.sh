@@ -28,6 +28,11 @@ if ! grep -q 'export PATH="$HOME/.swan:$PATH"' "$SHELL_RC"; then
     echo 'export PATH="$HOME/.swan:$PATH"' >> "$SHELL_RC"
 fi
 
+# ensure shell completion is loaded in zshrc
+if ! grep -q 'swan completion zsh' "$SHELL_RC"; then
+    echo 'source <(swan completion zsh)' >> "$SHELL_RC"
+fi
+
 # add path for current session
 PATH="$HOME/.swan:$PATH"
 
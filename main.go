@@ -2,35 +2,37 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"strings"
 
 	_ "github.com/rAlexander89/swan/commands/birb"
+	_ "github.com/rAlexander89/swan/commands/completion"
+	_ "github.com/rAlexander89/swan/commands/config"
+	_ "github.com/rAlexander89/swan/commands/db"
+	_ "github.com/rAlexander89/swan/commands/doctor"
 	_ "github.com/rAlexander89/swan/commands/domain"
+	_ "github.com/rAlexander89/swan/commands/help"
 	_ "github.com/rAlexander89/swan/commands/project"
 	_ "github.com/rAlexander89/swan/commands/project/db"
 	_ "github.com/rAlexander89/swan/commands/project/fly"
+	_ "github.com/rAlexander89/swan/commands/version"
 	"github.com/rAlexander89/swan/nodes"
+	"github.com/rAlexander89/swan/plugins"
+	"github.com/rAlexander89/swan/wizard"
+	"github.com/rAlexander89/swan/workspace"
 )
 
-type argument struct {
-	key       string
-	value     string
-	fields    []field
-	modifiers map[string]string // stores --flag=value pairs
-}
-
-type field struct {
-	name     string
-	dataType string
-}
-
 func main() {
-	args := os.Args[1:]
-	if len(args) == 0 {
-		fmt.Println("no command provided")
+	// global flags apply to every command, so they are taken out before dispatch
+	ctx, args, err := workspace.ParseFlags(os.Args[1:])
+	if err != nil {
+		fmt.Println(err)
 		os.Exit(1)
 	}
+	workspace.Set(ctx)
 
 	// load node tree
 	root, err := nodes.LoadNodes()
@@ -39,17 +41,68 @@ func main() {
 		os.Exit(1)
 	}
 
-	// get the command from args
-	cmd := args[0]
-	remainingArgs := args[1:]
+	if len(args) == 0 {
+		fmt.Println("no command provided")
+		printHelp(root)
+		os.Exit(1)
+	}
+
+	// walk args to the deepest matching node
+	node, remainingArgs := root.Resolve(args)
+
+	// unknown commands may be plugins, e.g. swan-gen on PATH runs as swan gen.
+	// a plugin handles its own --help, like git-<name> does
+	if node == root && !strings.HasPrefix(args[0], "-") {
+		if plugin, found := plugins.Find(args[0]); found {
+			exit(plugin.Run(args[1:]))
+		}
+	}
+
+	if node == root && !wantsHelp(args) {
+		fmt.Printf("unknown command: %s\n", args[0])
+		printSuggestions(root, args[0])
+		fmt.Println(root.Usage())
+		os.Exit(1)
+	}
+
+	// --help anywhere after the command prints the node's help
+	if wantsHelp(remainingArgs) {
+		printHelp(node)
+		return
+	}
+
+	// a command group without its own function needs a sub command
+	if !node.Runnable() && len(node.BranchMap) > 0 {
+		if len(remainingArgs) > 0 {
+			fmt.Printf("unknown command: %s %s\n", node.CommandLine(), remainingArgs[0])
+			printSuggestions(node, remainingArgs[0])
+		} else {
+			fmt.Printf("missing command for %s\n", node.CommandLine())
+		}
+		fmt.Print(node.Help())
+		os.Exit(1)
+	}
+
+	// parse and type check args against the node's schema
+	parsedArgs, err := node.ParseArgs(remainingArgs)
+
+	// ask for missing args when someone is there to answer
+	var missing *nodes.MissingArgError
+	if errors.As(err, &missing) && workspace.Interactive() {
+		remainingArgs, err = wizard.Run(node, remainingArgs)
+		if err == nil {
+			parsedArgs, err = node.ParseArgs(remainingArgs)
+		}
+	}
 
-	// find node and get registered function
-	node, exists := root.BranchMap[cmd]
-	if !exists {
-		fmt.Printf("unknown command: %s\n", cmd)
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println(node.Usage())
 		os.Exit(1)
 	}
 
+	// find registered function by the node's full path
+	cmd := node.Path()
 	fn, fnExists := nodes.GetCommand(cmd)
 	if !fnExists {
 		fmt.Printf("command %s not implemented\n", cmd)
@@ -57,8 +110,62 @@ func main() {
 	}
 	node.Run = fn
 
-	if err := node.Run(remainingArgs); err != nil {
-		fmt.Printf("error executing command: %v\n", err)
-		os.Exit(1)
+	// stage every write, so a failing command leaves the project as it was
+	exit(workspace.Run(func() error {
+		return node.Run(parsedArgs)
+	}))
+}
+
+// exit ends swan with the outcome of a command. a failed plugin has already
+// reported its error, so only its exit code is passed on
+func exit(err error) {
+	if err == nil {
+		os.Exit(0)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+
+	fmt.Printf("error executing command: %v\n", err)
+	os.Exit(1)
+}
+
+// printHelp prints the help of node. the root help also lists described plugins
+func printHelp(node *nodes.Node) {
+	if node.Prev == nil {
+		if root, err := plugins.LoadTree(); err == nil {
+			node = root
+		}
+	}
+
+	fmt.Print(node.Help())
+}
+
+// printSuggestions prints the sub commands of node that word is a likely typo of
+func printSuggestions(node *nodes.Node, word string) {
+	suggestions := node.Suggest(word)
+	if len(suggestions) == 0 {
+		return
+	}
+
+	fmt.Println("\ndid you mean?")
+	for _, s := range suggestions {
+		fmt.Printf("    %s %s\n", node.CommandLine(), s)
+	}
+	fmt.Println()
+}
+
+// wantsHelp reports whether args ask for help with -h or --help before any --
+func wantsHelp(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if arg == "-h" || arg == "--help" {
+			return true
+		}
 	}
+	return false
 }
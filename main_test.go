@@ -0,0 +1,16 @@
+// main_test.go
+package main
+
+import (
+	"testing"
+
+	"github.com/rAlexander89/swan/nodes"
+)
+
+// the command packages main imports register their functions in init, so the
+// whole registry is in place here
+func TestNodesMatchRegistry(t *testing.T) {
+	if err := nodes.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
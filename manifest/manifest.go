@@ -0,0 +1,214 @@
+// manifest/manifest.go
+//
+// Package manifest reads and writes swan.json, the record swan keeps of a
+// project: its module, database backend, the template it was created from and
+// the layers and operations generated for each domain. commands read it
+// instead of working the project out from go.mod and the directory layout.
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/version"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+// FileName is the manifest's name in the project root
+const FileName = "swan.json"
+
+// layers a domain can have generated
+const (
+	LayerDomain     = "domain"
+	LayerRepository = "repository"
+	LayerPort       = "port"
+	LayerService    = "service"
+	LayerHandler    = "handler"
+	LayerRoutes     = "routes"
+)
+
+// operations is the canonical order of the CRUDI letters
+const operations = "CRUDI"
+
+// ErrNotFound is returned by Load when the project has no swan.json
+var ErrNotFound = errors.New("no " + FileName + " found")
+
+// Manifest is the content of swan.json
+type Manifest struct {
+	Module   string             `json:"module"`
+	Database string             `json:"database"`
+	Template Template           `json:"template"`
+	Domains  map[string]*Domain `json:"domains,omitempty"`
+}
+
+// Template records the preset a project was created from and the swan version that did it
+type Template struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Domain records what was generated for a domain
+type Domain struct {
+	Layers []string `json:"layers"`
+	Ops    string   `json:"ops,omitempty"`
+}
+
+// New returns the manifest of a project swan new is about to create
+func New(module, database, template string) *Manifest {
+	return &Manifest{
+		Module:   module,
+		Database: database,
+		Template: Template{Name: template, Version: version.Version()},
+		Domains:  make(map[string]*Domain),
+	}
+}
+
+// Path returns the location of swan.json in the current project
+func Path() string {
+	return workspace.Path(FileName)
+}
+
+// Load reads swan.json from the current project
+func Load() (*Manifest, error) {
+	data, err := workspace.ReadFile(Path())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", FileName, err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", FileName, err)
+	}
+	if m.Domains == nil {
+		m.Domains = make(map[string]*Domain)
+	}
+
+	return &m, nil
+}
+
+// LoadOptional is Load for commands that also work on projects without
+// swan.json. it returns nil, nil when there is none
+func LoadOptional() (*Manifest, error) {
+	m, err := Load()
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return m, err
+}
+
+// Domains lists the domains recorded in swan.json or, for projects without
+// one, found in internal/core/domains
+func Domains() []string {
+	if m, err := LoadOptional(); err == nil && m != nil {
+		return m.DomainNames()
+	}
+
+	entries, err := os.ReadDir(workspace.Path("internal", "core", "domains"))
+	if err != nil {
+		return nil
+	}
+
+	var domains []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			domains = append(domains, utils.SnakeToPascal(entry.Name()))
+		}
+	}
+
+	return domains
+}
+
+// Save writes the manifest to swan.json in the current project
+func (m *Manifest) Save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %v", FileName, err)
+	}
+
+	if err := workspace.WriteFile(Path(), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", FileName, err)
+	}
+
+	return nil
+}
+
+// Update loads swan.json, applies fn and saves it. projects created before
+// swan wrote a manifest are skipped
+func Update(fn func(m *Manifest)) error {
+	m, err := LoadOptional()
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		workspace.Verbosef("no %s, not recording the change\n", FileName)
+		return nil
+	}
+
+	fn(m)
+	return m.Save()
+}
+
+// Domain returns the record of the domain called name, adding it if needed
+func (m *Manifest) Domain(name string) *Domain {
+	d, exists := m.Domains[name]
+	if !exists {
+		d = &Domain{}
+		m.Domains[name] = d
+	}
+	return d
+}
+
+// HasDomain reports whether swan generated the domain called name
+func (m *Manifest) HasDomain(name string) bool {
+	_, exists := m.Domains[name]
+	return exists
+}
+
+// DomainNames returns the recorded domains, sorted
+func (m *Manifest) DomainNames() []string {
+	names := make([]string, 0, len(m.Domains))
+	for name := range m.Domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddLayers records layers as generated
+func (d *Domain) AddLayers(layers ...string) {
+	for _, layer := range layers {
+		if !d.HasLayer(layer) {
+			d.Layers = append(d.Layers, layer)
+		}
+	}
+}
+
+// HasLayer reports whether the layer was generated
+func (d *Domain) HasLayer(layer string) bool {
+	for _, l := range d.Layers {
+		if l == layer {
+			return true
+		}
+	}
+	return false
+}
+
+// AddOps merges the CRUDI letters of ops into the recorded ones
+func (d *Domain) AddOps(ops string) {
+	var merged strings.Builder
+	for _, op := range operations {
+		if strings.ContainsRune(d.Ops, op) || strings.ContainsRune(strings.ToUpper(ops), op) {
+			merged.WriteRune(op)
+		}
+	}
+	d.Ops = merged.String()
+}
@@ -0,0 +1,56 @@
+// nodes/aliases.go
+package nodes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// aliasRegistry maps an alias, prefixed by its parent's canonical path, to the node name it
+// stands for. e.g. "d" -> "domain", or "db connect pg" -> "postgres"
+var aliasRegistry = make(map[string]string)
+
+// registerAliases records the aliases of every node under n, rejecting aliases that
+// collide with a sibling's name or alias
+func registerAliases(n *Node) error {
+	taken := make(map[string]string)
+	for name := range n.BranchMap {
+		taken[name] = name
+	}
+
+	for _, branch := range n.Branches() {
+		for _, alias := range branch.Aliases {
+			if owner, exists := taken[alias]; exists {
+				return fmt.Errorf("alias %s of %s is already used by %s", alias, branch.CommandLine(), owner)
+			}
+			taken[alias] = branch.Name
+			aliasRegistry[joinPath(n.Path(), alias)] = branch.Name
+		}
+
+		if err := registerAliases(branch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// canonicalPath replaces every alias in path with the name it stands for
+func canonicalPath(path string) string {
+	canonical := ""
+	for _, word := range strings.Fields(path) {
+		if name, exists := aliasRegistry[joinPath(canonical, word)]; exists {
+			word = name
+		}
+		canonical = joinPath(canonical, word)
+	}
+
+	return canonical
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + " " + name
+}
@@ -0,0 +1,287 @@
+// nodes/args.go
+package nodes
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// argument types understood by the parser
+const (
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeBool   = "bool"
+	TypeList   = "list" // collects values until the next flag
+)
+
+// Arg describes a positional argument or, when Flag is set, a flag of a command.
+// e.g. {"name": "operations", "type": "string", "flag": "c", "default": "CRUDI"}
+// is passed as -c CRU, -c=CRU, --operations CRU or --operations=CRU.
+// Complete names the completer used for shell completion of the arg's values.
+// Choices limits the accepted values and Prompt is the question the wizard asks for it
+type Arg struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Flag     string   `json:"flag,omitempty"`
+	Required bool     `json:"required"`
+	Default  string   `json:"default,omitempty"`
+	Complete string   `json:"complete,omitempty"`
+	Choices  []string `json:"choices,omitempty"`
+	Prompt   string   `json:"prompt,omitempty"`
+}
+
+// MissingArgError is returned by ParseArgs when a required arg was not given
+type MissingArgError struct {
+	Arg Arg
+}
+
+func (e *MissingArgError) Error() string {
+	return fmt.Sprintf("missing required argument: %s", e.Arg.label())
+}
+
+// IsFlag reports whether the arg is passed as a flag rather than by position
+func (a Arg) IsFlag() bool {
+	return a.Flag != ""
+}
+
+// Args holds the parsed and type checked arguments of a command, keyed by arg name
+type Args struct {
+	values map[string][]string
+	raw    []string
+}
+
+// NewArgs builds Args from already parsed values, e.g. for calling a command from another command
+func NewArgs(values map[string][]string) *Args {
+	if values == nil {
+		values = make(map[string][]string)
+	}
+	return &Args{values: values}
+}
+
+// Has reports whether the arg was given or has a default
+func (a *Args) Has(name string) bool {
+	_, exists := a.values[name]
+	return exists
+}
+
+// String returns the value of a string arg, or "" when it was not given
+func (a *Args) String(name string) string {
+	values := a.values[name]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Int returns the value of an int arg. values are type checked while parsing
+func (a *Args) Int(name string) int {
+	n, _ := strconv.Atoi(a.String(name))
+	return n
+}
+
+// Bool returns the value of a bool arg
+func (a *Args) Bool(name string) bool {
+	b, _ := strconv.ParseBool(a.String(name))
+	return b
+}
+
+// List returns every value of a list arg
+func (a *Args) List(name string) []string {
+	return a.values[name]
+}
+
+// Raw returns the args as they were typed, before parsing
+func (a *Args) Raw() []string {
+	return a.raw
+}
+
+// Schema returns the args declared in the node's config
+func (n *Node) Schema() []Arg {
+	if n.Config == nil {
+		return nil
+	}
+	return n.Config.Args
+}
+
+// ParseArgs parses raw against the node's schema. unknown flags, surplus args,
+// values of the wrong type and missing required args are all errors
+func (n *Node) ParseArgs(raw []string) (*Args, error) {
+	return n.parseArgs(raw, true)
+}
+
+// ParsePartial parses raw like ParseArgs but leaves missing args unset instead
+// of failing or applying defaults, e.g. to find out what a wizard has to ask for
+func (n *Node) ParsePartial(raw []string) (*Args, error) {
+	return n.parseArgs(raw, false)
+}
+
+func (n *Node) parseArgs(raw []string, complete bool) (*Args, error) {
+	schema := n.Schema()
+	args := &Args{values: make(map[string][]string), raw: raw}
+
+	var positional []Arg
+	for _, a := range schema {
+		if !a.IsFlag() {
+			positional = append(positional, a)
+		}
+	}
+
+	pos := 0
+	flagsDone := false
+	for i := 0; i < len(raw); i++ {
+		token := raw[i]
+
+		// -- ends flag parsing, everything after it is positional
+		if token == "--" && !flagsDone {
+			flagsDone = true
+			continue
+		}
+
+		if flagsDone || !isFlagToken(token) {
+			if pos >= len(positional) {
+				return nil, fmt.Errorf("unexpected argument: %s", token)
+			}
+
+			a := positional[pos]
+			if a.Type == TypeList {
+				// a list positional takes everything up to the next flag
+				for ; i < len(raw) && (flagsDone || !isFlagToken(raw[i])); i++ {
+					args.values[a.Name] = append(args.values[a.Name], raw[i])
+				}
+				i--
+			} else {
+				args.values[a.Name] = []string{token}
+			}
+			pos++
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(token, "-"), "=")
+		a, found := lookupFlag(schema, name, strings.HasPrefix(token, "--"))
+		if !found {
+			return nil, unknownFlagErr(schema, token)
+		}
+
+		switch {
+		case hasValue:
+			args.values[a.Name] = append(args.values[a.Name], value)
+		case a.Type == TypeBool:
+			args.values[a.Name] = []string{"true"}
+		case a.Type == TypeList:
+			start := len(args.values[a.Name])
+			for i+1 < len(raw) && !isFlagToken(raw[i+1]) {
+				i++
+				args.values[a.Name] = append(args.values[a.Name], raw[i])
+			}
+			if len(args.values[a.Name]) == start {
+				return nil, fmt.Errorf("flag %s expects at least one value", token)
+			}
+		default:
+			if i+1 >= len(raw) || isFlagToken(raw[i+1]) {
+				return nil, fmt.Errorf("flag %s expects a %s value", token, a.Type)
+			}
+			i++
+			args.values[a.Name] = []string{raw[i]}
+		}
+	}
+
+	for _, a := range schema {
+		if _, given := args.values[a.Name]; !given {
+			if !complete {
+				continue
+			}
+			if a.Required {
+				return nil, &MissingArgError{Arg: a}
+			}
+			if a.Default != "" {
+				args.values[a.Name] = []string{a.Default}
+			}
+			continue
+		}
+
+		if err := a.check(args.values[a.Name]); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+// check type checks the values given for the arg
+func (a Arg) check(values []string) error {
+	for _, v := range values {
+		switch a.Type {
+		case TypeInt:
+			if _, err := strconv.Atoi(v); err != nil {
+				return fmt.Errorf("%s expects an int, got %q", a.label(), v)
+			}
+		case TypeBool:
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("%s expects a bool, got %q", a.label(), v)
+			}
+		}
+	}
+
+	if a.Type != TypeList && len(values) > 1 {
+		return fmt.Errorf("%s given more than once", a.label())
+	}
+
+	if len(a.Choices) > 0 {
+		for _, v := range values {
+			if !slices.Contains(a.Choices, v) {
+				return fmt.Errorf("%s must be one of %s, got %q", a.label(), strings.Join(a.Choices, ", "), v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// label names the arg the way a user types it
+func (a Arg) label() string {
+	if a.IsFlag() {
+		return fmt.Sprintf("-%s <%s>", a.Flag, a.Name)
+	}
+	return fmt.Sprintf("<%s>", a.Name)
+}
+
+// lookupFlag finds the flag matching name. long flags (--name) match the arg name
+func lookupFlag(schema []Arg, name string, long bool) (Arg, bool) {
+	for _, a := range schema {
+		if !a.IsFlag() {
+			continue
+		}
+		if (!long && a.Flag == name) || (long && a.Name == name) {
+			return a, true
+		}
+	}
+	return Arg{}, false
+}
+
+// unknownFlagErr reports an unknown flag, suggesting the closest long flag
+func unknownFlagErr(schema []Arg, token string) error {
+	var names []string
+	for _, a := range schema {
+		if a.IsFlag() {
+			names = append(names, a.Name)
+		}
+	}
+
+	name, _, _ := strings.Cut(strings.TrimLeft(token, "-"), "=")
+	if suggestions := closest(name, names); len(suggestions) > 0 {
+		return fmt.Errorf("unknown flag: %s, did you mean --%s?", token, suggestions[0])
+	}
+
+	return fmt.Errorf("unknown flag: %s", token)
+}
+
+// isFlagToken reports whether token is a flag. negative numbers are values
+func isFlagToken(token string) bool {
+	if len(token) < 2 || token[0] != '-' {
+		return false
+	}
+	_, err := strconv.ParseFloat(token, 64)
+	return err != nil
+}
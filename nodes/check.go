@@ -0,0 +1,135 @@
+// nodes/check.go
+package nodes
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Problem is a mismatch between nodes.json and the command registry
+type Problem struct {
+	Path    string
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return fmt.Sprintf("%s: %s", p.Path, p.Message)
+}
+
+// Check cross checks the tree under root against the registered command functions.
+// it reports runnable nodes without a function, functions registered for paths that
+// are not in the tree and config package/function/file values that don't match the
+// function that was actually registered
+func Check(root *Node) []Problem {
+	var problems []Problem
+	paths := make(map[string]bool)
+
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if n != root {
+			paths[n.Path()] = true
+			problems = append(problems, checkNode(n)...)
+		}
+
+		for _, branch := range n.Branches() {
+			walk(branch)
+		}
+	}
+	walk(root)
+
+	for _, path := range RegisteredCommands() {
+		if !paths[path] {
+			problems = append(problems, Problem{
+				Path:    path,
+				Message: "function registered but there is no node for it in nodes.json",
+			})
+		}
+	}
+
+	return problems
+}
+
+// Verify loads the embedded tree and returns an error listing every problem found by Check.
+// it is meant to be called from a test in a package that imports every command package,
+// e.g. if err := nodes.Verify(); err != nil { t.Fatal(err) }
+func Verify() error {
+	root, err := LoadNodes()
+	if err != nil {
+		return err
+	}
+
+	problems := Check(root)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(problems))
+	for _, p := range problems {
+		lines = append(lines, "  "+p.String())
+	}
+
+	return fmt.Errorf("nodes.json and the command registry disagree:\n%s", strings.Join(lines, "\n"))
+}
+
+// checkNode compares a single node's config with its registered function
+func checkNode(n *Node) []Problem {
+	path := n.Path()
+	fn, registered := GetCommand(path)
+
+	if !n.Runnable() {
+		if registered {
+			return []Problem{{Path: path, Message: "function registered but the node has no config function"}}
+		}
+		if len(n.BranchMap) == 0 {
+			return []Problem{{Path: path, Message: "node has no config function and no branches"}}
+		}
+		return nil
+	}
+
+	if !registered {
+		return []Problem{{
+			Path:    path,
+			Message: fmt.Sprintf("no function registered for %s.%s", n.Config.Package, n.Config.Function),
+		}}
+	}
+
+	var problems []Problem
+
+	pc := reflect.ValueOf(fn).Pointer()
+	runtimeFn := runtime.FuncForPC(pc)
+	if runtimeFn == nil {
+		return []Problem{{Path: path, Message: "registered function could not be resolved"}}
+	}
+
+	expected := fmt.Sprintf("%s/%s.%s", modulePath(), n.Config.Package, n.Config.Function)
+	if actual := runtimeFn.Name(); actual != expected {
+		problems = append(problems, Problem{
+			Path: path,
+			Message: fmt.Sprintf("config points at %s.%s but the registered function is %s",
+				n.Config.Package, n.Config.Function, strings.TrimPrefix(actual, modulePath()+"/")),
+		})
+	}
+
+	if n.Config.File != "" {
+		file, _ := runtimeFn.FileLine(pc)
+		if filepath.Base(file) != n.Config.File {
+			problems = append(problems, Problem{
+				Path:    path,
+				Message: fmt.Sprintf("config file is %s but the function is defined in %s", n.Config.File, filepath.Base(file)),
+			})
+		}
+	}
+
+	return problems
+}
+
+// modulePath returns swan's module path, derived from this package's import path
+func modulePath() string {
+	return strings.TrimSuffix(reflect.TypeOf(Node{}).PkgPath(), "/nodes")
+}
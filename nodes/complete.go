@@ -0,0 +1,134 @@
+// nodes/complete.go
+package nodes
+
+import (
+	"sort"
+	"strings"
+)
+
+// completerRegistry maps a completer name used by Arg.Complete to the function producing candidates
+var completerRegistry = make(map[string]func(prefix string) []string)
+
+// RegisterCompleter registers fn under name so args can declare "complete": name in nodes.json
+func RegisterCompleter(name string, fn func(prefix string) []string) {
+	completerRegistry[name] = fn
+}
+
+func GetCompleter(name string) (func(prefix string) []string, bool) {
+	fn, exists := completerRegistry[name]
+	return fn, exists
+}
+
+// Complete returns completion candidates for the last of words, the word being typed.
+// the words before it are resolved against the tree the same way the dispatcher does
+func (n *Node) Complete(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+
+	current := words[len(words)-1]
+	node, rest := n.Resolve(words[:len(words)-1])
+	_, flags := node.args()
+
+	// value of the flag right before the current word
+	if len(rest) > 0 {
+		if f, ok := flagFor(flags, rest[len(rest)-1]); ok && f.Type != TypeBool {
+			return node.completeArg(f, current)
+		}
+	}
+
+	if strings.HasPrefix(current, "-") {
+		var candidates []string
+		for _, f := range flags {
+			candidates = append(candidates, "-"+f.Flag, "--"+f.Name)
+		}
+		candidates = append(candidates, "--help")
+		return filterPrefix(candidates, current)
+	}
+
+	var candidates []string
+
+	// sub commands are only offered before any of the node's own args
+	if len(rest) == 0 {
+		for _, branch := range node.VisibleBranches() {
+			candidates = append(candidates, branch.Name)
+			candidates = append(candidates, branch.Aliases...)
+		}
+	}
+
+	if a, ok := node.positionalAt(rest); ok {
+		candidates = append(candidates, node.completeArg(a, current)...)
+	}
+
+	return filterPrefix(candidates, current)
+}
+
+// positionalAt returns the positional arg that the next word fills, given the words before it
+func (n *Node) positionalAt(rest []string) (Arg, bool) {
+	positional, flags := n.args()
+
+	count := 0
+	for i := 0; i < len(rest); i++ {
+		if f, ok := flagFor(flags, rest[i]); ok {
+			if f.Type != TypeBool && !strings.Contains(rest[i], "=") {
+				i++ // skip the flag value
+			}
+			continue
+		}
+		count++
+	}
+
+	if count < len(positional) {
+		return positional[count], true
+	}
+
+	// a trailing list positional keeps taking values
+	if len(positional) > 0 && positional[len(positional)-1].Type == TypeList {
+		return positional[len(positional)-1], true
+	}
+
+	return Arg{}, false
+}
+
+// completeArg runs the completer declared for the arg
+func (n *Node) completeArg(a Arg, prefix string) []string {
+	if len(a.Choices) > 0 {
+		return filterPrefix(a.Choices, prefix)
+	}
+
+	if a.Complete == "" {
+		return nil
+	}
+
+	fn, exists := GetCompleter(a.Complete)
+	if !exists {
+		return nil
+	}
+
+	return filterPrefix(fn(prefix), prefix)
+}
+
+// flagFor returns the flag declared for token, e.g. -c, --operations or --operations=CRU
+func flagFor(flags []Arg, token string) (Arg, bool) {
+	if !isFlagToken(token) {
+		return Arg{}, false
+	}
+
+	name, _, _ := strings.Cut(strings.TrimLeft(token, "-"), "=")
+	return lookupFlag(flags, name, strings.HasPrefix(token, "--"))
+}
+
+// filterPrefix returns the sorted, de-duplicated candidates starting with prefix
+func filterPrefix(candidates []string, prefix string) []string {
+	seen := make(map[string]bool)
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) && !seen[c] {
+			seen[c] = true
+			matches = append(matches, c)
+		}
+	}
+
+	sort.Strings(matches)
+	return matches
+}
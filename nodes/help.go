@@ -0,0 +1,186 @@
+// nodes/help.go
+package nodes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// globalFlagsHelp lists the flags handled by the workspace package before dispatch
+const globalFlagsHelp = `
+global flags:
+  -C, --dir <path>   run in the project at path instead of the working directory
+  --dry-run          print planned writes and commands without touching disk
+  -v, --verbose      print more detail
+  -q, --quiet        print only errors
+  -i, --interactive  ask for missing args on stdin, even when it is not a terminal
+`
+
+// args splits the args declared in the node's config into positional args and flags
+func (n *Node) args() (positional []Arg, flags []Arg) {
+	for _, a := range n.Schema() {
+		if a.IsFlag() {
+			flags = append(flags, a)
+			continue
+		}
+		positional = append(positional, a)
+	}
+
+	return positional, flags
+}
+
+// Branches returns the node's sub commands sorted by name
+func (n *Node) Branches() []*Node {
+	branches := make([]*Node, 0, len(n.BranchMap))
+	for _, branch := range n.BranchMap {
+		branches = append(branches, branch)
+	}
+
+	sort.Slice(branches, func(i, j int) bool {
+		return branches[i].Name < branches[j].Name
+	})
+
+	return branches
+}
+
+// VisibleBranches returns the sub commands that are not hidden, sorted by name
+func (n *Node) VisibleBranches() []*Node {
+	var branches []*Node
+	for _, branch := range n.Branches() {
+		if !branch.Hidden {
+			branches = append(branches, branch)
+		}
+	}
+
+	return branches
+}
+
+// Runnable reports whether the node has a config pointing at a command function
+func (n *Node) Runnable() bool {
+	return n.Config != nil && n.Config.Function != ""
+}
+
+// CommandLine returns the command as typed in a terminal, e.g. "swan db connect"
+func (n *Node) CommandLine() string {
+	root := n
+	for root.Prev != nil {
+		root = root.Prev
+	}
+
+	if n == root {
+		return root.Name
+	}
+
+	return root.Name + " " + n.Path()
+}
+
+// Usage returns the one line usage of the node built from its args and branches
+func (n *Node) Usage() string {
+	var lines []string
+
+	if len(n.BranchMap) > 0 {
+		lines = append(lines, fmt.Sprintf("%s <command>", n.CommandLine()))
+	}
+
+	if n.Runnable() {
+		parts := []string{n.CommandLine()}
+		positional, flags := n.args()
+
+		for _, a := range positional {
+			name := a.Name
+			if a.Type == TypeList {
+				name += "..."
+			}
+
+			if a.Required {
+				parts = append(parts, fmt.Sprintf("<%s>", name))
+			} else {
+				parts = append(parts, fmt.Sprintf("[%s]", name))
+			}
+		}
+
+		for _, f := range flags {
+			if f.Type == TypeBool {
+				parts = append(parts, fmt.Sprintf("[-%s]", f.Flag))
+				continue
+			}
+
+			if f.Required {
+				parts = append(parts, fmt.Sprintf("-%s <%s>", f.Flag, f.Name))
+			} else {
+				parts = append(parts, fmt.Sprintf("[-%s <%s>]", f.Flag, f.Name))
+			}
+		}
+
+		lines = append(lines, strings.Join(parts, " "))
+	}
+
+	if len(lines) == 0 {
+		return "usage: " + n.CommandLine()
+	}
+
+	return "usage: " + strings.Join(lines, "\n       ")
+}
+
+// Help returns the full help text of the node: usage, sub commands, args and flags
+func (n *Node) Help() string {
+	var b strings.Builder
+
+	if n.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", n.Description)
+	}
+	b.WriteString(n.Usage())
+	b.WriteString("\n")
+
+	if len(n.Aliases) > 0 {
+		fmt.Fprintf(&b, "\naliases: %s\n", strings.Join(n.Aliases, ", "))
+	}
+
+	if branches := n.VisibleBranches(); len(branches) > 0 {
+		b.WriteString("\ncommands:\n")
+		for _, branch := range branches {
+			name := branch.Name
+			if len(branch.Aliases) > 0 {
+				name += " (" + strings.Join(branch.Aliases, ", ") + ")"
+			}
+			fmt.Fprintf(&b, "  %-16s %s\n", name, branch.Description)
+		}
+	}
+
+	positional, flags := n.args()
+	if len(positional) > 0 {
+		b.WriteString("\narguments:\n")
+		for _, a := range positional {
+			fmt.Fprintf(&b, "  %-16s %-8s %s\n", a.Name, a.Type, requiredLabel(a))
+		}
+	}
+
+	if len(flags) > 0 {
+		b.WriteString("\nflags:\n")
+		for _, f := range flags {
+			name := fmt.Sprintf("-%s %s", f.Flag, f.Name)
+			fmt.Fprintf(&b, "  %-16s %-8s %s\n", name, f.Type, requiredLabel(f))
+		}
+	}
+
+	if n.Prev == nil {
+		b.WriteString(globalFlagsHelp)
+	}
+
+	if len(n.BranchMap) > 0 {
+		fmt.Fprintf(&b, "\nrun '%s <command> --help' for more information on a command\n", n.CommandLine())
+	}
+
+	return b.String()
+}
+
+func requiredLabel(a Arg) string {
+	if a.Required {
+		return "required"
+	}
+	if a.Default != "" {
+		return fmt.Sprintf("optional (default %s)", a.Default)
+	}
+	return "optional"
+}
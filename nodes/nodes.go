@@ -4,28 +4,38 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 //go:embed nodes.json
 var nodesConfig []byte
 
 type Node struct {
-	Name      string               `json:"name"`
-	Prev      *Node                `json:"prev"`
-	Run       func([]string) error `json:"-"`
-	Config    *Config              `json:"config"`
-	BranchMap map[string]*Node     `json:"branches"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Hidden      bool              `json:"hidden"` // left out of help and completion
+	Aliases     []string          `json:"aliases"`
+	Prev        *Node             `json:"prev"`
+	Run         func(*Args) error `json:"-"`
+	Config      *Config           `json:"config"`
+	BranchMap   map[string]*Node  `json:"branches"`
 }
 
 type Config struct {
 	Package  string `json:"package"`
 	File     string `json:"file"`
 	Function string `json:"function"`
-	Args     *[]struct {
-		Name     *string `json:"name"`
-		Type     *string `json:"type"`
-		Required bool    `json:"required"`
-	} `json:"args,omitempty"`
+	Args     []Arg  `json:"args,omitempty"`
+}
+
+// nodeData mirrors a single entry of nodes.json before it is linked into the tree
+type nodeData struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Hidden      bool                 `json:"hidden"`
+	Aliases     []string             `json:"aliases"`
+	Config      *Config              `json:"config"`
+	BranchMap   map[string]*nodeData `json:"branches"`
 }
 
 func NewNode(name string, prev *Node, config *Config) *Node {
@@ -45,32 +55,93 @@ func NewNode(name string, prev *Node, config *Config) *Node {
 
 func LoadNodes() (*Node, error) {
 	// unmarshal directly from embedded data
-	var temp struct {
-		Name      string  `json:"name"` // root.Name == 'swan'
-		Config    *Config `json:"config"`
-		BranchMap map[string]*struct {
-			Name      string           `json:"name"`
-			Config    *Config          `json:"config"`
-			BranchMap map[string]*Node `json:"branches"`
-		} `json:"branches"`
-	}
-
+	var temp nodeData
 	if err := json.Unmarshal(nodesConfig, &temp); err != nil {
 		return nil, fmt.Errorf("error parsing config: %v", err)
 	}
 
-	// create swan node
+	// create swan node, root.Name == 'swan'
 	root := NewNode(temp.Name, nil, temp.Config)
+	root.Description = temp.Description
+
+	// create every branch below swan
+	if err := buildBranches(root, temp.BranchMap); err != nil {
+		return nil, err
+	}
+
+	// aliases are checked once the whole tree exists so they can't shadow a branch
+	if err := registerAliases(root); err != nil {
+		return nil, err
+	}
+
+	// swan
+	return root, nil
+}
+
+// buildBranches recursively links the branches of data under parent
+func buildBranches(parent *Node, branches map[string]*nodeData) error {
+	for branchName, branchData := range branches {
+		if branchData == nil {
+			return fmt.Errorf("branch %s has no definition", branchName)
+		}
 
-	// create branch nodes for swan
-	for branchName, branchData := range temp.BranchMap {
 		if branchName != branchData.Name {
-			return nil, fmt.Errorf("branch name mismatch: %s != %s",
+			return fmt.Errorf("branch name mismatch: %s != %s",
 				branchName, branchData.Name)
 		}
-		NewNode(branchData.Name, root, branchData.Config)
+
+		node := NewNode(branchData.Name, parent, branchData.Config)
+		node.Description = branchData.Description
+		node.Hidden = branchData.Hidden
+		node.Aliases = branchData.Aliases
+		if err := buildBranches(node, branchData.BranchMap); err != nil {
+			return err
+		}
 	}
 
-	// swan
-	return root, nil
+	return nil
+}
+
+// Path returns the space separated command path of the node, excluding the root.
+// e.g. the postgres node under db > connect has the path "db connect postgres"
+func (n *Node) Path() string {
+	var parts []string
+	for node := n; node != nil && node.Prev != nil; node = node.Prev {
+		parts = append([]string{node.Name}, parts...)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Branch returns the sub command called name or declaring name as an alias
+func (n *Node) Branch(name string) (*Node, bool) {
+	if branch, exists := n.BranchMap[name]; exists {
+		return branch, true
+	}
+
+	for _, branch := range n.BranchMap {
+		for _, alias := range branch.Aliases {
+			if alias == name {
+				return branch, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// Resolve walks args down the tree and returns the deepest matching node
+// along with the args that were not consumed by the walk
+func (n *Node) Resolve(args []string) (*Node, []string) {
+	node := n
+	i := 0
+	for ; i < len(args); i++ {
+		next, exists := node.Branch(args[i])
+		if !exists {
+			break
+		}
+		node = next
+	}
+
+	return node, args[i:]
 }
@@ -1,13 +1,34 @@
 // nodes/registry.go
 package nodes
 
-var commandRegistry = make(map[string]func([]string) error)
+import "sort"
 
-func RegisterCommand(name string, fn func([]string) error) {
-	commandRegistry[name] = fn
+// commandRegistry maps a full command path (e.g. "db connect postgres") to its function
+var commandRegistry = make(map[string]func(*Args) error)
+
+// RegisterCommand registers fn under the full, space separated path of its node
+func RegisterCommand(path string, fn func(*Args) error) {
+	commandRegistry[path] = fn
 }
 
-func GetCommand(name string) (func([]string) error, bool) {
-	fn, exists := commandRegistry[name]
+// GetCommand returns the function registered for path. aliases in path resolve
+// to the command they stand for, so "d" finds the function of "domain"
+func GetCommand(path string) (func(*Args) error, bool) {
+	fn, exists := commandRegistry[canonicalPath(path)]
 	return fn, exists
 }
+
+// RegisteredCommands returns the paths of every registered command, sorted
+func RegisteredCommands() []string {
+	return sortedKeys(commandRegistry)
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,82 @@
+// nodes/suggest.go
+package nodes
+
+import "sort"
+
+// Suggest returns the visible sub commands and aliases of n that are a likely
+// typo of word, closest first. short aliases only match exactly, so they are
+// left out of suggestions. commands are suggested whatever their length
+func (n *Node) Suggest(word string) []string {
+	var candidates []string
+	for _, branch := range n.VisibleBranches() {
+		candidates = append(candidates, branch.Name)
+		for _, alias := range branch.Aliases {
+			if len(alias) >= 3 {
+				candidates = append(candidates, alias)
+			}
+		}
+	}
+
+	return closest(word, candidates)
+}
+
+// closest returns the candidates within a typo's distance of word, closest first
+func closest(word string, candidates []string) []string {
+	type match struct {
+		name     string
+		distance int
+	}
+
+	limit := len(word)/3 + 1
+	var matches []match
+	for _, c := range candidates {
+		if d := editDistance(word, c); d <= limit {
+			matches = append(matches, match{name: c, distance: d})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, m.name)
+	}
+
+	return names
+}
+
+// editDistance returns the Damerau-Levenshtein distance between a and b,
+// counting a swap of two adjacent letters (hacth -> hatch) as one edit
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
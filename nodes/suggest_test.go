@@ -0,0 +1,38 @@
+// nodes/suggest_test.go
+package nodes
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	root, err := LoadNodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"dbb", []string{"db"}},
+		{"hacth", []string{"hatch"}},
+		{"domian", []string{"domain"}},
+		{"vresion", []string{"version"}},
+		{"zzzzzz", nil},
+		// one letter aliases, d for domain, are not suggested for a typo
+		{"dd", []string{"db"}},
+	}
+
+	for _, tt := range tests {
+		if got := root.Suggest(tt.word); !slices.Equal(got, tt.want) {
+			t.Errorf("Suggest(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+
+	connect, _ := root.Resolve([]string{"db", "connect"})
+	if got := connect.Suggest("postgre"); !slices.Equal(got, []string{"postgres"}) {
+		t.Errorf("Suggest(postgre) under db connect = %q", got)
+	}
+}
@@ -0,0 +1,368 @@
+// plugins/plugins.go
+//
+// Package plugins runs external swan commands. a plugin is any executable named
+// swan-<name> in ~/.swan/plugins or on PATH, and runs as `swan <name> [args...]`.
+//
+// a plugin receives its args as argv, plus this environment:
+//
+//	SWAN_PROJECT_ROOT  absolute path of the project swan was run in, or -C/--dir
+//	SWAN_MODULE        module path from the project's go.mod, empty outside a project
+//	SWAN_DRY_RUN       "1" when --dry-run was given, the plugin must not write to disk
+//	SWAN_VERBOSITY     "quiet", "normal" or "verbose"
+//	SWAN_CONTEXT       the same values and the args as JSON, see Context
+//
+// swan's global flags are taken out of the args before the plugin runs.
+//
+// a plugin may also answer `swan-<name> --describe` by printing a Description as
+// JSON. described plugins are listed in `swan help` and in shell completion.
+// answers are cached in ~/.swan/plugins/describe-cache.json, and a plugin is
+// only asked again once its executable changes.
+// a plugin parses its own args, the described args are only used for help and completion.
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rAlexander89/swan/nodes"
+	"github.com/rAlexander89/swan/utils"
+	"github.com/rAlexander89/swan/workspace"
+)
+
+// Prefix is the executable name prefix that marks a swan plugin
+const Prefix = "swan-"
+
+// describeTimeout bounds how long a plugin may take to answer --describe
+const describeTimeout = 2 * time.Second
+
+// cacheFile holds the --describe answers of plugins, in the plugin directory
+const cacheFile = "describe-cache.json"
+
+// Context is passed to a plugin as JSON in SWAN_CONTEXT
+type Context struct {
+	ProjectRoot string   `json:"project_root"`
+	Module      string   `json:"module"`
+	DryRun      bool     `json:"dry_run"`
+	Verbosity   string   `json:"verbosity"`
+	Args        []string `json:"args"`
+}
+
+// Description is what a plugin prints for --describe
+type Description struct {
+	Description string      `json:"description"`
+	Args        []nodes.Arg `json:"args,omitempty"`
+}
+
+// Plugin is an executable found on disk
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// Dir returns the swan plugin directory, ~/.swan/plugins
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return filepath.Join(home, ".swan", "plugins"), nil
+}
+
+// Find returns the plugin for the command name. ~/.swan/plugins wins over PATH
+func Find(name string) (Plugin, bool) {
+	if dir, err := Dir(); err == nil {
+		path := filepath.Join(dir, Prefix+name)
+		if isExecutable(path) {
+			return Plugin{Name: name, Path: path}, true
+		}
+	}
+
+	path, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return Plugin{}, false
+	}
+
+	return Plugin{Name: name, Path: path}, true
+}
+
+// List returns every plugin in ~/.swan/plugins and on PATH, sorted by name
+func List() []Plugin {
+	var dirs []string
+	if dir, err := Dir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+
+	found := make(map[string]Plugin)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, isPlugin := strings.CutPrefix(entry.Name(), Prefix)
+			if !isPlugin || name == "" || entry.IsDir() {
+				continue
+			}
+
+			// the first directory to provide a name wins, like PATH lookup
+			if _, exists := found[name]; exists {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if isExecutable(path) {
+				found[name] = Plugin{Name: name, Path: path}
+			}
+		}
+	}
+
+	plugins := make([]Plugin, 0, len(found))
+	for _, p := range found {
+		plugins = append(plugins, p)
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+
+	return plugins
+}
+
+// Describe runs the plugin with --describe and parses its answer
+func (p Plugin) Describe() (*Description, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), describeTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, p.Path, "--describe").Output()
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s does not describe itself: %v", p.Name, err)
+	}
+
+	var description Description
+	if err := json.Unmarshal(output, &description); err != nil {
+		return nil, fmt.Errorf("plugin %s printed an invalid description: %v", p.Name, err)
+	}
+
+	return &description, nil
+}
+
+// Run executes the plugin with args, connected to swan's stdin, stdout and stderr
+func (p Plugin) Run(args []string) error {
+	pluginCtx, err := newContext(args)
+	if err != nil {
+		return err
+	}
+
+	contextJSON, err := json.Marshal(pluginCtx)
+	if err != nil {
+		return fmt.Errorf("error marshaling plugin context: %v", err)
+	}
+
+	dryRun := ""
+	if pluginCtx.DryRun {
+		dryRun = "1"
+	}
+
+	cmd := exec.Command(p.Path, args...)
+	cmd.Dir = pluginCtx.ProjectRoot
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"SWAN_PROJECT_ROOT="+pluginCtx.ProjectRoot,
+		"SWAN_MODULE="+pluginCtx.Module,
+		"SWAN_DRY_RUN="+dryRun,
+		"SWAN_VERBOSITY="+pluginCtx.Verbosity,
+		"SWAN_CONTEXT="+string(contextJSON),
+	)
+
+	return cmd.Run()
+}
+
+// Attach adds a node to root for every plugin that answers --describe, so plugins
+// show up in help and completion. plugins never shadow a built in command.
+// a plugin is only run when its cached answer is missing or stale
+func Attach(root *nodes.Node) {
+	cache := loadCache()
+	listed := make(map[string]bool)
+
+	for _, p := range List() {
+		if _, exists := root.Branch(p.Name); exists {
+			continue
+		}
+		listed[p.Path] = true
+
+		description, cached := cache.lookup(p)
+		if !cached {
+			description, _ = p.Describe()
+			cache.store(p, description)
+		}
+		if description == nil {
+			continue
+		}
+
+		node := nodes.NewNode(p.Name, root, &nodes.Config{
+			Package:  "plugin",
+			File:     p.Path,
+			Function: Prefix + p.Name,
+			Args:     description.Args,
+		})
+		node.Description = description.Description
+
+		plugin := p
+		nodes.RegisterCommand(p.Name, func(args *nodes.Args) error {
+			return plugin.Run(args.Raw())
+		})
+	}
+
+	cache.prune(listed)
+	cache.save()
+}
+
+// cacheEntry is the --describe answer of a plugin executable as it was on disk
+type cacheEntry struct {
+	ModTime     int64        `json:"mod_time"`
+	Size        int64        `json:"size"`
+	Description *Description `json:"description,omitempty"` // nil when the plugin did not describe itself
+}
+
+// describeCache maps the path of a plugin to its last --describe answer
+type describeCache struct {
+	entries map[string]cacheEntry
+	changed bool
+}
+
+// loadCache reads the cache file. a missing or unreadable cache is empty
+func loadCache() *describeCache {
+	cache := &describeCache{entries: make(map[string]cacheEntry)}
+	if path, err := cachePath(); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, &cache.entries)
+		}
+	}
+	return cache
+}
+
+// lookup returns the cached answer of p, unless p changed since it was cached
+func (c *describeCache) lookup(p Plugin) (*Description, bool) {
+	entry, exists := c.entries[p.Path]
+	if !exists {
+		return nil, false
+	}
+
+	info, err := os.Stat(p.Path)
+	if err != nil || info.ModTime().UnixNano() != entry.ModTime || info.Size() != entry.Size {
+		return nil, false
+	}
+	return entry.Description, true
+}
+
+// store caches the answer of p, nil when it did not describe itself
+func (c *describeCache) store(p Plugin, description *Description) {
+	info, err := os.Stat(p.Path)
+	if err != nil {
+		return
+	}
+
+	c.entries[p.Path] = cacheEntry{
+		ModTime:     info.ModTime().UnixNano(),
+		Size:        info.Size(),
+		Description: description,
+	}
+	c.changed = true
+}
+
+// prune drops the entries of plugins that were not listed
+func (c *describeCache) prune(listed map[string]bool) {
+	for path := range c.entries {
+		if !listed[path] {
+			delete(c.entries, path)
+			c.changed = true
+		}
+	}
+}
+
+// save writes the cache when it changed. the cache is an optimization, so
+// failing to write it is not an error. a dry run writes nothing
+func (c *describeCache) save() {
+	if !c.changed || workspace.DryRun() {
+		return
+	}
+
+	path, err := cachePath()
+	if err != nil {
+		return
+	}
+
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err == nil {
+		c.changed = false
+	}
+}
+
+func cachePath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, cacheFile), nil
+}
+
+// LoadTree loads the node tree with described plugins attached
+func LoadTree() (*nodes.Node, error) {
+	root, err := nodes.LoadNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	Attach(root)
+	return root, nil
+}
+
+// newContext collects the project values handed to a plugin
+func newContext(args []string) (Context, error) {
+	// plugins may run outside a project, so a missing go.mod leaves module empty
+	module, _ := utils.GetProjectName()
+
+	if args == nil {
+		args = []string{}
+	}
+
+	verbosity := "normal"
+	switch workspace.Current().Level {
+	case workspace.Quiet:
+		verbosity = "quiet"
+	case workspace.Verbose:
+		verbosity = "verbose"
+	}
+
+	return Context{
+		ProjectRoot: workspace.Root(),
+		Module:      module,
+		DryRun:      workspace.DryRun(),
+		Verbosity:   verbosity,
+		Args:        args,
+	}, nil
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return info.Mode()&0111 != 0
+}
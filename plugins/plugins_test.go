@@ -0,0 +1,94 @@
+// plugins/plugins_test.go
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rAlexander89/swan/nodes"
+)
+
+// writePlugin writes an executable swan-<name> to dir that logs every
+// --describe to calls and answers with description
+func writePlugin(t *testing.T, dir, name, calls, description string) string {
+	t.Helper()
+
+	script := "#!/bin/sh\necho describe >> " + calls + "\necho '" + description + "'\n"
+	path := filepath.Join(dir, Prefix+name)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func countCalls(t *testing.T, calls string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(calls)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "describe")
+}
+
+func loadRoot(t *testing.T) *nodes.Node {
+	t.Helper()
+
+	root, err := LoadTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestAttachCachesDescriptions(t *testing.T) {
+	home, bin := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", bin)
+
+	calls := filepath.Join(t.TempDir(), "calls")
+	path := writePlugin(t, bin, "gen", calls, `{"description": "generate things"}`)
+	writePlugin(t, bin, "mute", calls, `not json`)
+
+	root := loadRoot(t)
+	gen, exists := root.Branch("gen")
+	if !exists || gen.Description != "generate things" {
+		t.Fatalf("gen was not attached with its description: %+v", gen)
+	}
+	if _, exists := root.Branch("mute"); exists {
+		t.Fatal("a plugin without a valid description was attached")
+	}
+	if n := countCalls(t, calls); n != 2 {
+		t.Fatalf("expected both plugins to be described once, got %d calls", n)
+	}
+
+	// neither plugin changed, so both answers come from the cache
+	root = loadRoot(t)
+	if gen, _ := root.Branch("gen"); gen == nil || gen.Description != "generate things" {
+		t.Fatal("gen was not attached from the cache")
+	}
+	if n := countCalls(t, calls); n != 2 {
+		t.Fatalf("expected the cached answers to be used, got %d calls", n)
+	}
+
+	// a changed executable is described again
+	writePlugin(t, bin, "gen", calls, `{"description": "generate more things"}`)
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	root = loadRoot(t)
+	if gen, _ := root.Branch("gen"); gen == nil || gen.Description != "generate more things" {
+		t.Fatal("gen was not described again after it changed")
+	}
+	if n := countCalls(t, calls); n != 3 {
+		t.Fatalf("expected only the changed plugin to be described, got %d calls", n)
+	}
+}
@@ -0,0 +1,18 @@
+#!/bin/bash
+
+# generates a full project in a temp dir for each database backend and
+# template, and checks it builds and vets. run from the swan checkout:
+#
+#   ./selftest.sh
+#
+# the checks are the go tests of commands/project, go test -short skips them
+
+set -e
+
+# check if go is installed
+if ! command -v go &> /dev/null; then
+    echo "go is not installed"
+    exit 1
+fi
+
+go test -count=1 -run 'TestGenerated' ./commands/project/ "$@"
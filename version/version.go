@@ -0,0 +1,67 @@
+// version/version.go
+package version
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// Info describes the swan build that is running
+type Info struct {
+	Version   string // module version, e.g. v0.3.1, or devel for local builds
+	Revision  string // vcs revision the binary was built from, if known
+	Modified  bool   // the working tree had uncommitted changes at build time
+	GoVersion string
+}
+
+// Read returns the build info embedded in the swan binary
+func Read() Info {
+	info := Info{Version: "devel"}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info.GoVersion = build.GoVersion
+	if v := build.Main.Version; v != "" && v != "(devel)" {
+		info.Version = v
+	}
+
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+
+	return info
+}
+
+// String returns the version as stamped into generated files, e.g. v0.3.1 or devel+1a2b3c4d5e6f-dirty
+func (i Info) String() string {
+	if i.Version != "devel" || i.Revision == "" {
+		return i.Version
+	}
+
+	v := i.Version + "+" + shortRevision(i.Revision)
+	if i.Modified {
+		v += "-dirty"
+	}
+	return v
+}
+
+// Version returns the version of the running swan binary
+func Version() string {
+	return Read().String()
+}
+
+func shortRevision(revision string) string {
+	revision = strings.TrimSpace(revision)
+	if len(revision) > 12 {
+		return revision[:12]
+	}
+	return revision
+}
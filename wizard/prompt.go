@@ -0,0 +1,165 @@
+// wizard/prompt.go
+package wizard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrNoAnswer is returned when input ends before a question without a default was answered
+var ErrNoAnswer = errors.New("no answer given")
+
+// Prompter asks questions line by line, so answers can be typed or piped from a file
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// Option is an entry of a checklist, picked by its key
+type Option struct {
+	Key   string
+	Label string
+}
+
+// readLine returns the next line of input without its line break
+func (p *Prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// Ask asks question and returns the answer, or def when the answer is empty
+func (p *Prompter) Ask(question, def string) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", question)
+		}
+
+		answer, err := p.readLine()
+		if err != nil {
+			if def != "" {
+				fmt.Fprintln(p.out)
+				return def, nil
+			}
+			return "", fmt.Errorf("%s: %w", question, ErrNoAnswer)
+		}
+
+		if answer == "" {
+			answer = def
+		}
+		if answer != "" {
+			return answer, nil
+		}
+	}
+}
+
+// AskOptional asks question and allows an empty answer, e.g. to end a loop
+func (p *Prompter) AskOptional(question string) (string, error) {
+	fmt.Fprintf(p.out, "%s: ", question)
+
+	answer, err := p.readLine()
+	if err == io.EOF {
+		fmt.Fprintln(p.out)
+		return "", nil
+	}
+	return answer, err
+}
+
+// Select asks for one of choices, by value or by its number in the list
+func (p *Prompter) Select(question string, choices []string, def string) (string, error) {
+	for i, choice := range choices {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, choice)
+	}
+
+	for {
+		answer, err := p.Ask(question, def)
+		if err != nil {
+			return "", err
+		}
+
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(choices) {
+			return choices[n-1], nil
+		}
+		if slices.Contains(choices, answer) {
+			return answer, nil
+		}
+
+		fmt.Fprintf(p.out, "please pick one of: %s\n", strings.Join(choices, ", "))
+	}
+}
+
+// Confirm asks a yes/no question
+func (p *Prompter) Confirm(question string, def bool) (bool, error) {
+	defStr := "n"
+	if def {
+		defStr = "y"
+	}
+
+	for {
+		answer, err := p.Ask(question+" (y/n)", defStr)
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// Checklist lists options and asks which to keep. the answer is the picked keys
+// typed together or separated by spaces or commas, e.g. "CRU" or "C, R, U"
+func (p *Prompter) Checklist(question string, options []Option, def []string) ([]string, error) {
+	for _, option := range options {
+		mark := " "
+		if slices.Contains(def, option.Key) {
+			mark = "x"
+		}
+		fmt.Fprintf(p.out, "  [%s] %s  %s\n", mark, option.Key, option.Label)
+	}
+
+	for {
+		answer, err := p.Ask(question, strings.Join(def, ""))
+		if err != nil {
+			return nil, err
+		}
+
+		picked, ok := pickOptions(answer, options)
+		if ok {
+			return picked, nil
+		}
+
+		fmt.Fprintln(p.out, "please pick from the keys listed above")
+	}
+}
+
+// pickOptions returns the keys of options named in answer, in option order
+func pickOptions(answer string, options []Option) ([]string, bool) {
+	answer = strings.NewReplacer(",", "", " ", "").Replace(answer)
+
+	var picked []string
+	for _, option := range options {
+		if strings.Contains(strings.ToUpper(answer), strings.ToUpper(option.Key)) {
+			picked = append(picked, option.Key)
+			answer = strings.Replace(strings.ToUpper(answer), strings.ToUpper(option.Key), "", 1)
+		}
+	}
+
+	return picked, answer == "" && len(picked) > 0
+}
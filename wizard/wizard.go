@@ -0,0 +1,119 @@
+// wizard/wizard.go
+//
+// Package wizard asks for the args a command is missing. the answers are turned
+// back into command line tokens, so a wizard run and a typed command go through
+// the same parser and produce the same result.
+package wizard
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rAlexander89/swan/nodes"
+)
+
+// Step asks the command specific questions of a wizard, e.g. the fields of a domain.
+// it gets the args parsed so far and returns the tokens to append to the command line
+type Step func(p *Prompter, args *nodes.Args) ([]string, error)
+
+// stepRegistry maps a full command path to its wizard step
+var stepRegistry = make(map[string]Step)
+
+// RegisterStep registers the wizard step of the command at path
+func RegisterStep(path string, step Step) {
+	stepRegistry[path] = step
+}
+
+// Run asks on stdin for the args missing from raw and returns raw with the answers appended
+func Run(node *nodes.Node, raw []string) ([]string, error) {
+	return RunWith(NewPrompter(os.Stdin, os.Stdout), node, raw)
+}
+
+// RunWith is Run with the questions asked through p
+func RunWith(p *Prompter, node *nodes.Node, raw []string) ([]string, error) {
+	args, err := node.ParsePartial(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := append([]string{}, raw...)
+
+	for _, a := range node.Schema() {
+		if args.Has(a.Name) || (!a.Required && a.Prompt == "") {
+			continue
+		}
+
+		answer, err := ask(p, a)
+		if err != nil {
+			return nil, err
+		}
+		if len(answer) == 0 {
+			continue
+		}
+
+		if a.IsFlag() {
+			if a.Type == nodes.TypeBool {
+				tokens = append(tokens, fmt.Sprintf("--%s=%s", a.Name, answer[0]))
+				continue
+			}
+			tokens = append(tokens, "-"+a.Flag)
+		}
+		tokens = append(tokens, answer...)
+	}
+
+	if step, exists := stepRegistry[node.Path()]; exists {
+		args, err := node.ParsePartial(tokens)
+		if err != nil {
+			return nil, err
+		}
+
+		extra, err := step(p, args)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, extra...)
+	}
+
+	return tokens, nil
+}
+
+// ask asks the question for a single arg and returns its value as tokens
+func ask(p *Prompter, a nodes.Arg) ([]string, error) {
+	question := a.Prompt
+	if question == "" {
+		question = a.Name
+	}
+
+	switch {
+	case len(a.Choices) > 0:
+		answer, err := p.Select(question, a.Choices, a.Default)
+		return []string{answer}, err
+	case a.Type == nodes.TypeBool:
+		answer, err := p.Confirm(question, a.Default == "true")
+		return []string{fmt.Sprint(answer)}, err
+	case a.Type == nodes.TypeList:
+		answer, err := p.Ask(question, a.Default)
+		return strings.Fields(answer), err
+	}
+
+	if a.Complete != "" {
+		if fn, exists := nodes.GetCompleter(a.Complete); exists {
+			if existing := fn(""); len(existing) > 0 {
+				question = fmt.Sprintf("%s (%s)", question, strings.Join(existing, ", "))
+			}
+		}
+	}
+
+	// optional args without a default may be skipped with an empty answer
+	if !a.Required && a.Default == "" {
+		answer, err := p.AskOptional(question + " (optional)")
+		if answer == "" {
+			return nil, err
+		}
+		return []string{answer}, err
+	}
+
+	answer, err := p.Ask(question, a.Default)
+	return []string{answer}, err
+}
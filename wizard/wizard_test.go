@@ -0,0 +1,115 @@
+// wizard/wizard_test.go
+package wizard_test
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	_ "github.com/rAlexander89/swan/commands/domain"
+	_ "github.com/rAlexander89/swan/commands/project/db"
+	"github.com/rAlexander89/swan/nodes"
+	"github.com/rAlexander89/swan/wizard"
+)
+
+// TestRunWith answers the wizard of a command from piped input and checks the
+// tokens it returns parse to the same args as the typed command line
+func TestRunWith(t *testing.T) {
+	root, err := nodes.LoadNodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		raw     []string
+		answers []string
+		typed   string
+	}{
+		{
+			name:    "new asks every question",
+			command: "new",
+			answers: []string{"svc", "github.com/acme/svc", "3", "worker"},
+			typed:   "svc -m github.com/acme/svc -d sqlite -t worker",
+		},
+		{
+			name:    "new keeps given args and takes defaults",
+			command: "new",
+			raw:     []string{"svc", "--docker"},
+			answers: []string{"", "", ""},
+			typed:   "svc --docker -d postgres -t api",
+		},
+		{
+			name:    "domain asks for fields and tags",
+			command: "domain",
+			answers: []string{"BlogPost", "title", "string", "published", "bool", "", "json, db"},
+			typed:   "BlogPost -f Title string Published bool -t json db",
+		},
+		{
+			name:    "domain without fields",
+			command: "domain",
+			answers: []string{"Tag", ""},
+			typed:   "Tag",
+		},
+		{
+			name:    "hatch picks operations from the checklist",
+			command: "hatch",
+			answers: []string{"User", "C, R"},
+			typed:   "User -c CR",
+		},
+		{
+			name:    "hatch keeps given operations",
+			command: "hatch",
+			raw:     []string{"-c", "C"},
+			answers: []string{"User"},
+			typed:   "User -c C",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, rest := root.Resolve([]string{tt.command})
+			if len(rest) != 0 || node.Path() != tt.command {
+				t.Fatalf("command %s not found", tt.command)
+			}
+
+			input := strings.Join(tt.answers, "\n") + "\n"
+			tokens, err := wizard.RunWith(wizard.NewPrompter(strings.NewReader(input), io.Discard), node, tt.raw)
+			if err != nil {
+				t.Fatalf("RunWith: %v", err)
+			}
+
+			got, err := node.ParseArgs(tokens)
+			if err != nil {
+				t.Fatalf("parsing the wizard tokens %q: %v", tokens, err)
+			}
+			want, err := node.ParseArgs(strings.Fields(tt.typed))
+			if err != nil {
+				t.Fatalf("parsing the typed command line: %v", err)
+			}
+
+			for _, a := range node.Schema() {
+				if got.Has(a.Name) != want.Has(a.Name) || !slices.Equal(got.List(a.Name), want.List(a.Name)) {
+					t.Errorf("%s: wizard gave %q (set %t), typed gave %q (set %t)",
+						a.Name, got.List(a.Name), got.Has(a.Name), want.List(a.Name), want.Has(a.Name))
+				}
+			}
+		})
+	}
+}
+
+// TestRunWithNoAnswer checks a required question left unanswered is an error
+func TestRunWithNoAnswer(t *testing.T) {
+	root, err := nodes.LoadNodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, _ := root.Resolve([]string{"hatch"})
+
+	_, err = wizard.RunWith(wizard.NewPrompter(strings.NewReader(""), io.Discard), node, nil)
+	if err == nil {
+		t.Fatal("expected an error when the domain is not answered")
+	}
+}
@@ -0,0 +1,142 @@
+// workspace/fs.go
+package workspace
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// planned holds the content of files written during a dry run, so later
+// generators in the same run can read what earlier ones would have written
+var planned = make(map[string][]byte)
+
+// plannedDirs holds the directories created during a dry run, so Exists
+// reports them the way a real run would
+var plannedDirs = make(map[string]bool)
+
+// WriteFile writes data to path, or prints the planned write during a dry run.
+// inside Run the write is staged until the command succeeds
+func WriteFile(path string, data []byte, perm fs.FileMode) error {
+	if current.DryRun {
+		planned[path] = data
+		fmt.Printf("would write %s (%d bytes)\n", Rel(path), len(data))
+		return nil
+	}
+
+	Verbosef("writing %s\n", Rel(path))
+	if tx != nil {
+		return tx.stage(path, data, perm)
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+// MkdirAll creates path and its parents, or prints the planned directory during a dry run
+func MkdirAll(path string, perm fs.FileMode) error {
+	if current.DryRun {
+		if !Exists(path) {
+			Verbosef("would create %s/\n", Rel(path))
+		}
+		for dir := filepath.Clean(path); !Exists(dir); dir = filepath.Dir(dir) {
+			plannedDirs[dir] = true
+		}
+		return nil
+	}
+
+	if tx != nil {
+		tx.stageDir(path)
+		return nil
+	}
+	return os.MkdirAll(path, perm)
+}
+
+// Create returns a writer for path. the file is written when the writer is closed
+func Create(path string) (io.WriteCloser, error) {
+	return &bufferedFile{path: path}, nil
+}
+
+// ReadFile reads path, preferring content planned during a dry run or staged by Run
+func ReadFile(path string) ([]byte, error) {
+	if data, exists := planned[path]; exists {
+		return data, nil
+	}
+	if tx != nil {
+		if data, exists := tx.read(path); exists {
+			return data, nil
+		}
+	}
+	return os.ReadFile(path)
+}
+
+// Exists reports whether path exists on disk, was planned during a dry run or is staged
+func Exists(path string) bool {
+	if _, exists := planned[path]; exists || plannedDirs[filepath.Clean(path)] {
+		return true
+	}
+	if tx != nil {
+		if _, exists := tx.files[path]; exists || tx.planned[path] {
+			return true
+		}
+	}
+
+	_, err := os.Stat(path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// Command runs name with args in dir and returns its combined output.
+// during a dry run the command is printed instead
+func Command(dir, name string, args ...string) ([]byte, error) {
+	return CommandEnv(dir, nil, name, args...)
+}
+
+// CommandEnv is Command with env added to the environment. inside Run, the
+// staged files are written first so the command sees them, and the go.mod and
+// go.sum the go tool may rewrite are journaled
+func CommandEnv(dir string, env []string, name string, args ...string) ([]byte, error) {
+	line := strings.Join(append(append([]string{}, env...), append([]string{name}, args...)...), " ")
+	if current.DryRun {
+		fmt.Printf("would run %s\n", line)
+		return nil, nil
+	}
+
+	if tx != nil {
+		if err := tx.flush(); err != nil {
+			return nil, err
+		}
+		if name == "go" {
+			for _, file := range []string{"go.mod", "go.sum"} {
+				if err := tx.touch(filepath.Join(dir, file)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	Verbosef("running %s\n", line)
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd.CombinedOutput()
+}
+
+// bufferedFile holds the content of a file until it is closed
+type bufferedFile struct {
+	path string
+	buf  bytes.Buffer
+}
+
+func (f *bufferedFile) Write(p []byte) (int, error) {
+	return f.buf.Write(p)
+}
+
+func (f *bufferedFile) Close() error {
+	return WriteFile(f.path, f.buf.Bytes(), 0644)
+}
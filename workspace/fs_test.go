@@ -0,0 +1,33 @@
+// workspace/fs_test.go
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestDryRunDirs checks a dry run reports the directories it would create as
+// existing, and still writes nothing
+func TestDryRunDirs(t *testing.T) {
+	dir := t.TempDir()
+	quietly(t, dir)
+	current.DryRun = true
+	t.Cleanup(func() { clear(plannedDirs) })
+
+	deep := filepath.Join(dir, "internal", "core", "domains")
+	if err := MkdirAll(deep, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{deep, filepath.Join(dir, "internal"), filepath.Join(dir, "internal", "core") + "/"} {
+		if !Exists(path) {
+			t.Errorf("Exists(%s) = false after a dry MkdirAll", path)
+		}
+	}
+	if Exists(filepath.Join(dir, "cmd")) {
+		t.Error("Exists reports a directory that was never created")
+	}
+	if state := snapshot(t, dir); len(state) != 1 {
+		t.Errorf("dry run changed the tree: %v", state)
+	}
+}
@@ -0,0 +1,211 @@
+// workspace/module.go
+package workspace
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Root returns the root of the project swan works on: the nearest directory at
+// or above Dir with a go.mod. a go.work workspace found first resolves to its
+// module when it uses exactly one. without either, Root is Dir
+func Root() string {
+	root, err := findRoot(Dir())
+	if err != nil {
+		return Dir()
+	}
+	return root
+}
+
+// FindModuleRoot walks up from dir to the nearest directory with a go.mod
+func FindModuleRoot(dir string) (string, bool) {
+	for {
+		if Exists(filepath.Join(dir, "go.mod")) {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// findRoot is FindModuleRoot, also resolving a go.work met on the way up
+func findRoot(dir string) (string, error) {
+	start := dir
+	for {
+		if Exists(filepath.Join(dir, "go.mod")) {
+			return dir, nil
+		}
+
+		if workPath := filepath.Join(dir, "go.work"); Exists(workPath) {
+			return workspaceModule(workPath)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no go.mod found in %s or any parent directory", start)
+		}
+		dir = parent
+	}
+}
+
+// workspaceModule returns the single module a go.work uses
+func workspaceModule(workPath string) (string, error) {
+	data, err := ReadFile(workPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", workPath, err)
+	}
+
+	directives, err := parseModFile(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %v", workPath, err)
+	}
+
+	var uses []string
+	for _, d := range directives {
+		if d.verb == "use" && len(d.args) > 0 {
+			uses = append(uses, filepath.Join(filepath.Dir(workPath), d.args[0]))
+		}
+	}
+
+	if len(uses) != 1 {
+		return "", fmt.Errorf("%s uses %d modules, run swan inside one of them or pass -C", workPath, len(uses))
+	}
+	return uses[0], nil
+}
+
+// ModulePath returns the module path declared by the project's go.mod
+func ModulePath() (string, error) {
+	root, err := findRoot(Dir())
+	if err != nil {
+		return "", err
+	}
+
+	data, err := ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod: %v", err)
+	}
+
+	return ParseModulePath(data)
+}
+
+// ParseModulePath returns the path of the module directive in go.mod content.
+// comments, quoted paths and the block form are understood
+func ParseModulePath(data []byte) (string, error) {
+	directives, err := parseModFile(data)
+	if err != nil {
+		return "", err
+	}
+
+	for _, d := range directives {
+		if d.verb != "module" {
+			continue
+		}
+		if len(d.args) != 1 {
+			return "", fmt.Errorf("go.mod:%d: invalid module declaration", d.line)
+		}
+		return d.args[0], nil
+	}
+
+	return "", fmt.Errorf("go.mod has no module declaration")
+}
+
+// directive is a line of a go.mod or go.work, e.g. require example.com/x v1.0.0.
+// lines inside a block carry the verb of the block
+type directive struct {
+	verb string
+	args []string
+	line int
+}
+
+// parseModFile splits go.mod or go.work content into directives
+func parseModFile(data []byte) ([]directive, error) {
+	var directives []directive
+	block := ""
+
+	for i, text := range strings.Split(string(data), "\n") {
+		line := i + 1
+		tokens, err := tokenize(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		switch {
+		case block != "" && tokens[0] == ")":
+			block = ""
+		case block != "":
+			directives = append(directives, directive{verb: block, args: tokens, line: line})
+		case len(tokens) == 2 && tokens[1] == "(":
+			block = tokens[0]
+		default:
+			directives = append(directives, directive{verb: tokens[0], args: tokens[1:], line: line})
+		}
+	}
+
+	if block != "" {
+		return nil, fmt.Errorf("unterminated %s block", block)
+	}
+	return directives, nil
+}
+
+// tokenize splits a line into words, unquoting "interpreted" and `raw` strings
+// and dropping // comments
+func tokenize(text string) ([]string, error) {
+	var tokens []string
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case strings.HasPrefix(text[i:], "//"):
+			return tokens, nil
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"' || c == '`':
+			end := closingQuote(text, i)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string")
+			}
+
+			word, err := strconv.Unquote(text[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted string %s", text[i:end+1])
+			}
+			tokens = append(tokens, word)
+			i = end + 1
+		default:
+			start := i
+			for i < len(text) && !unicode.IsSpace(rune(text[i])) && !strings.HasPrefix(text[i:], "//") && text[i] != '(' && text[i] != ')' {
+				i++
+			}
+			tokens = append(tokens, text[start:i])
+		}
+	}
+
+	return tokens, nil
+}
+
+// closingQuote returns the index of the quote closing the string opened at start
+func closingQuote(text string, start int) int {
+	quote := text[start]
+	for i := start + 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case text[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
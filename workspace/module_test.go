@@ -0,0 +1,188 @@
+// workspace/module_test.go
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseModulePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		gomod   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "plain",
+			gomod: "module example.com/svc\n\ngo 1.22\n",
+			want:  "example.com/svc",
+		},
+		{
+			name:  "leading comment",
+			gomod: "// the service module\n// module example.com/wrong\nmodule example.com/svc\n",
+			want:  "example.com/svc",
+		},
+		{
+			name:  "quoted path",
+			gomod: "module \"example.com/svc\"\n",
+			want:  "example.com/svc",
+		},
+		{
+			name:  "raw path",
+			gomod: "module `example.com/svc`\n",
+			want:  "example.com/svc",
+		},
+		{
+			name:  "block form",
+			gomod: "module (\n\texample.com/svc\n)\n\ngo 1.22\n",
+			want:  "example.com/svc",
+		},
+		{
+			name:  "trailing comment",
+			gomod: "module example.com/svc // Deprecated: use example.com/svc/v2\n",
+			want:  "example.com/svc",
+		},
+		{
+			name:  "comment without a space",
+			gomod: "module example.com/svc//note\n",
+			want:  "example.com/svc",
+		},
+		{
+			name:  "module after require block",
+			gomod: "go 1.22\n\nrequire (\n\texample.com/dep v1.0.0 // indirect\n)\n\nmodule example.com/svc\n",
+			want:  "example.com/svc",
+		},
+		{
+			name:    "unterminated block",
+			gomod:   "module example.com/svc\n\nrequire (\n\texample.com/dep v1.0.0\n",
+			wantErr: "unterminated require block",
+		},
+		{
+			name:    "unterminated string",
+			gomod:   "module \"example.com/svc\n",
+			wantErr: "line 1: unterminated string",
+		},
+		{
+			name:    "two paths",
+			gomod:   "module example.com/a example.com/b\n",
+			wantErr: "go.mod:1: invalid module declaration",
+		},
+		{
+			name:    "no module",
+			gomod:   "// just a comment\ngo 1.22\n",
+			wantErr: "no module declaration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseModulePath([]byte(tt.gomod))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkspaceModule(t *testing.T) {
+	tests := []struct {
+		name    string
+		gowork  string
+		want    string // relative to the go.work
+		wantErr string
+	}{
+		{
+			name:    "no use",
+			gowork:  "go 1.22\n",
+			wantErr: "uses 0 modules",
+		},
+		{
+			name:   "one use",
+			gowork: "go 1.22\n\nuse ./svc\n",
+			want:   "svc",
+		},
+		{
+			name:   "one use in a block",
+			gowork: "go 1.22\n\nuse (\n\t./svc // the service\n)\n",
+			want:   "svc",
+		},
+		{
+			name:   "quoted use",
+			gowork: "go 1.22\n\nuse \"./my svc\"\n",
+			want:   "my svc",
+		},
+		{
+			name:    "two uses",
+			gowork:  "go 1.22\n\nuse (\n\t./api\n\t./worker\n)\n",
+			wantErr: "uses 2 modules",
+		},
+		{
+			name:    "unterminated use block",
+			gowork:  "go 1.22\n\nuse (\n\t./api\n",
+			wantErr: "unterminated use block",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			workPath := filepath.Join(dir, "go.work")
+			if err := os.WriteFile(workPath, []byte(tt.gowork), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := workspaceModule(workPath)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(dir, tt.want); got != want {
+				t.Fatalf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestFindRoot checks a go.mod is found from a subdirectory, and that a go.work
+// met first resolves to the module it uses
+func TestFindRoot(t *testing.T) {
+	dir := t.TempDir()
+	svc := filepath.Join(dir, "svc")
+	nested := filepath.Join(svc, "internal", "core")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(svc, "go.mod"), []byte("module example.com/svc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.work"), []byte("go 1.22\n\nuse ./svc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, start := range []string{nested, svc, dir} {
+		root, err := findRoot(start)
+		if err != nil {
+			t.Fatalf("from %s: %v", start, err)
+		}
+		if root != svc {
+			t.Fatalf("from %s: got %s, want %s", start, root, svc)
+		}
+	}
+}
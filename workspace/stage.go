@@ -0,0 +1,265 @@
+// workspace/stage.go
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// a transaction stages the writes of a command in a temporary directory and
+// commits them together. every change it makes on disk is journaled first, so
+// a failure puts each touched file and directory back the way it was
+type transaction struct {
+	dir     string                // staging area holding the content of staged files
+	files   map[string]stagedFile // staged files by target path
+	order   []string              // targets in the order they were staged
+	dirs    []string              // directories to create, parents first
+	planned map[string]bool       // staged directories, for Exists
+	journal []change              // changes made on disk, oldest first
+	touched map[string]bool       // paths already journaled
+}
+
+type stagedFile struct {
+	tmp  string
+	perm fs.FileMode
+}
+
+// change is a journal entry: path was created, or overwritten and backed up
+type change struct {
+	path    string
+	created bool
+	backup  []byte
+	perm    fs.FileMode
+}
+
+var tx *transaction
+
+// keptError is a failure that leaves the changes of the command in place
+type keptError struct {
+	err error
+}
+
+func (e *keptError) Error() string { return e.err.Error() }
+func (e *keptError) Unwrap() error { return e.err }
+
+// KeepChanges marks err as a failure Run commits the changes of, e.g. generated
+// code that does not build and is better fixed by hand than thrown away
+func KeepChanges(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &keptError{err: err}
+}
+
+// Run calls fn with its writes staged, commits them when it succeeds and rolls
+// every change back when it fails. an error marked by KeepChanges is returned
+// after committing. a dry run writes nothing, so fn runs as is
+func Run(fn func() error) error {
+	if current.DryRun {
+		return fn()
+	}
+
+	if err := begin(); err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		var kept *keptError
+		if !errors.As(err, &kept) {
+			rollback()
+			return err
+		}
+
+		if commitErr := commit(); commitErr != nil {
+			rollback()
+			return commitErr
+		}
+		return err
+	}
+
+	if err := commit(); err != nil {
+		rollback()
+		return err
+	}
+
+	return nil
+}
+
+func begin() error {
+	dir, err := os.MkdirTemp("", "swan-stage-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %v", err)
+	}
+
+	tx = &transaction{
+		dir:     dir,
+		files:   make(map[string]stagedFile),
+		planned: make(map[string]bool),
+		touched: make(map[string]bool),
+	}
+	return nil
+}
+
+// commit moves everything staged into place and ends the transaction
+func commit() error {
+	if err := tx.flush(); err != nil {
+		return err
+	}
+
+	os.RemoveAll(tx.dir)
+	tx = nil
+	return nil
+}
+
+// rollback drops what is staged, undoes the journal newest first and ends the transaction
+func rollback() {
+	t := tx
+	tx = nil
+	defer os.RemoveAll(t.dir)
+
+	if len(t.journal) == 0 {
+		return
+	}
+
+	Printf("rolling back %d change(s)\n", len(t.journal))
+	for i := len(t.journal) - 1; i >= 0; i-- {
+		c := t.journal[i]
+
+		var err error
+		switch {
+		case c.created:
+			Verbosef("removing %s\n", Rel(c.path))
+			err = os.Remove(c.path)
+		default:
+			// the file in place may be a new one with other permissions
+			Verbosef("restoring %s\n", Rel(c.path))
+			if err = os.WriteFile(c.path, c.backup, c.perm); err == nil {
+				err = os.Chmod(c.path, c.perm)
+			}
+		}
+
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "failed to roll back %s: %v\n", c.path, err)
+		}
+	}
+}
+
+// stage writes data to the staging area in place of path
+func (t *transaction) stage(path string, data []byte, perm fs.FileMode) error {
+	f, exists := t.files[path]
+	if !exists {
+		tmp, err := os.CreateTemp(t.dir, "file-")
+		if err != nil {
+			return fmt.Errorf("failed to stage %s: %v", path, err)
+		}
+		tmp.Close()
+
+		f.tmp = tmp.Name()
+		t.order = append(t.order, path)
+	}
+	f.perm = perm
+	t.files[path] = f
+
+	return os.WriteFile(f.tmp, data, 0600)
+}
+
+// stageDir plans path and its missing parents
+func (t *transaction) stageDir(path string) {
+	var missing []string
+	for dir := path; !Exists(dir); dir = filepath.Dir(dir) {
+		missing = append(missing, dir)
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	for i := len(missing) - 1; i >= 0; i-- {
+		t.dirs = append(t.dirs, missing[i])
+		t.planned[missing[i]] = true
+	}
+}
+
+// read returns the staged content of path
+func (t *transaction) read(path string) ([]byte, bool) {
+	f, exists := t.files[path]
+	if !exists {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(f.tmp)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// flush writes everything staged so far to disk, journaling each change. the
+// transaction stays open, e.g. so a command can run against the files
+func (t *transaction) flush() error {
+	for _, dir := range t.dirs {
+		if _, err := os.Stat(dir); err == nil {
+			continue
+		}
+
+		if err := os.Mkdir(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %v", dir, err)
+		}
+		t.journal = append(t.journal, change{path: dir, created: true})
+	}
+	t.dirs = nil
+	t.planned = make(map[string]bool)
+
+	for _, path := range t.order {
+		f := t.files[path]
+		data, err := os.ReadFile(f.tmp)
+		if err != nil {
+			return fmt.Errorf("failed to read staged %s: %v", path, err)
+		}
+
+		if err := t.touch(path); err != nil {
+			return err
+		}
+
+		// write next to the target and rename, so path is never half written
+		next := path + ".swan-tmp"
+		if err := os.WriteFile(next, data, f.perm); err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+		if err := os.Rename(next, path); err != nil {
+			os.Remove(next)
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+	}
+	t.files = make(map[string]stagedFile)
+	t.order = nil
+
+	return nil
+}
+
+// touch journals the current state of path before it is changed on disk
+func (t *transaction) touch(path string) error {
+	if t.touched[path] {
+		return nil
+	}
+	t.touched[path] = true
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.journal = append(t.journal, change{path: path, created: true})
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to back up %s: %v", path, err)
+	}
+
+	backup, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to back up %s: %v", path, err)
+	}
+
+	t.journal = append(t.journal, change{path: path, backup: backup, perm: info.Mode().Perm()})
+	return nil
+}
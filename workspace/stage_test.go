@@ -0,0 +1,235 @@
+// workspace/stage_test.go
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// snapshot records every file and directory under root with its permissions
+// and content
+func snapshot(t *testing.T, root string) map[string]string {
+	t.Helper()
+
+	state := make(map[string]string)
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		if entry.IsDir() {
+			state[rel] = fmt.Sprintf("dir %v", info.Mode().Perm())
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		state[rel] = fmt.Sprintf("file %v %q", info.Mode().Perm(), data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func writeFile(t *testing.T, path, content string, perm fs.FileMode) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// quietly runs the test at the quiet level, so rollbacks print nothing
+func quietly(t *testing.T, dir string) {
+	t.Helper()
+
+	previous := current
+	Set(&Context{Dir: dir, Level: Quiet})
+	t.Cleanup(func() { Set(previous) })
+}
+
+// TestRunRollsBackAfterFlush fails a command after its writes were flushed for
+// a go command, and checks the tree is restored exactly
+func TestRunRollsBackAfterFlush(t *testing.T) {
+	dir := t.TempDir()
+	quietly(t, dir)
+
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/svc\n", 0644)
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n", 0644)
+	if err := os.Mkdir(filepath.Join(dir, "internal"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	before := snapshot(t, dir)
+
+	failure := errors.New("generator failed")
+	err := Run(func() error {
+		if err := MkdirAll(filepath.Join(dir, "internal", "core", "domains"), 0755); err != nil {
+			return err
+		}
+		if err := WriteFile(filepath.Join(dir, "internal", "core", "domains", "user.go"), []byte("package domains\n"), 0644); err != nil {
+			return err
+		}
+		if err := WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+			return err
+		}
+
+		// a go command sees the staged files and may rewrite go.mod and go.sum
+		if out, err := Command(dir, "go", "version"); err != nil {
+			return fmt.Errorf("go version: %v: %s", err, out)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "internal", "core", "domains", "user.go")); err != nil {
+			return fmt.Errorf("the staged file was not flushed before the command: %v", err)
+		}
+		writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/svc\n\nrequire example.com/dep v1.0.0\n", 0644)
+		writeFile(t, filepath.Join(dir, "go.sum"), "example.com/dep v1.0.0 h1:abc=\n", 0644)
+
+		// staged after the flush, never written
+		if err := MkdirAll(filepath.Join(dir, "cmd"), 0755); err != nil {
+			return err
+		}
+		if err := WriteFile(filepath.Join(dir, "cmd", "main.go"), []byte("package main\n"), 0644); err != nil {
+			return err
+		}
+
+		return failure
+	})
+
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected the error of fn, got %v", err)
+	}
+	if tx != nil {
+		t.Fatal("the transaction was left open")
+	}
+
+	after := snapshot(t, dir)
+	if !maps.Equal(before, after) {
+		t.Fatalf("the tree was not restored\nbefore: %v\nafter:  %v", before, after)
+	}
+}
+
+// TestRunRestoresOverwrittenFiles checks overwritten files get their content
+// and permissions back
+func TestRunRestoresOverwrittenFiles(t *testing.T) {
+	dir := t.TempDir()
+	quietly(t, dir)
+
+	secret := filepath.Join(dir, ".env")
+	script := filepath.Join(dir, "run.sh")
+	writeFile(t, secret, "APP_DB_URI=postgres://secret\n", 0600)
+	writeFile(t, script, "#!/bin/sh\necho run\n", 0755)
+	before := snapshot(t, dir)
+
+	err := Run(func() error {
+		if err := WriteFile(secret, []byte("# generated\n"), 0644); err != nil {
+			return err
+		}
+		if err := WriteFile(script, []byte("#!/bin/sh\necho generated\n"), 0644); err != nil {
+			return err
+		}
+		// written twice, the first backup is the one restored
+		if err := tx.flush(); err != nil {
+			return err
+		}
+		if err := WriteFile(secret, []byte("# generated again\n"), 0644); err != nil {
+			return err
+		}
+		if err := tx.flush(); err != nil {
+			return err
+		}
+		return errors.New("generator failed")
+	})
+	if err == nil {
+		t.Fatal("expected the error of fn")
+	}
+
+	after := snapshot(t, dir)
+	if !maps.Equal(before, after) {
+		t.Fatalf("the files were not restored\nbefore: %v\nafter:  %v", before, after)
+	}
+}
+
+// TestRunCommits checks a successful run writes everything staged and removes
+// its staging directory
+func TestRunCommits(t *testing.T) {
+	dir := t.TempDir()
+	quietly(t, dir)
+
+	var staging string
+	err := Run(func() error {
+		staging = tx.dir
+		if err := MkdirAll(filepath.Join(dir, "configs"), 0755); err != nil {
+			return err
+		}
+		if err := WriteFile(filepath.Join(dir, "configs", "dev.json"), []byte("{}"), 0644); err != nil {
+			return err
+		}
+
+		// staged content is what later generators read
+		data, err := ReadFile(filepath.Join(dir, "configs", "dev.json"))
+		if err != nil || string(data) != "{}" {
+			return fmt.Errorf("staged file not readable: %q, %v", data, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "configs")); !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("a directory was created before commit")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := snapshot(t, dir)
+	if _, exists := got["configs"]; !exists {
+		t.Fatal("configs was not created")
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "configs", "dev.json")); err != nil || string(data) != "{}" {
+		t.Fatalf("configs/dev.json was not written: %q, %v", data, err)
+	}
+	if _, err := os.Stat(staging); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatal("the staging directory was left behind")
+	}
+}
+
+// TestRunKeepChanges checks a failure marked by KeepChanges is returned with
+// the changes of fn committed
+func TestRunKeepChanges(t *testing.T) {
+	dir := t.TempDir()
+	quietly(t, dir)
+
+	path := filepath.Join(dir, "routes.go")
+	failure := errors.New("go vet reported 1 problem(s)")
+	err := Run(func() error {
+		if err := WriteFile(path, []byte("package routes\n"), 0644); err != nil {
+			return err
+		}
+		return KeepChanges(failure)
+	})
+
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected the error of fn, got %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "package routes\n" {
+		t.Fatalf("the change was not kept: %q, %v", data, err)
+	}
+	if KeepChanges(nil) != nil {
+		t.Fatal("KeepChanges(nil) is not nil")
+	}
+}
@@ -0,0 +1,172 @@
+// workspace/workspace.go
+//
+// Package workspace holds the state set by swan's global flags: the project
+// directory commands work in, dry-run and the output level. generators resolve
+// paths and write files through it instead of using the process working directory.
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// output levels set by -q/--quiet and -v/--verbose
+const (
+	Quiet = iota
+	Normal
+	Verbose
+)
+
+// Context is the shared state of a swan run
+type Context struct {
+	Dir         string // absolute path of the project directory
+	DryRun      bool
+	Level       int
+	Interactive bool // ask for missing args even when stdin is not a terminal
+}
+
+var current = &Context{Level: Normal}
+
+// Current returns the context of this run
+func Current() *Context {
+	return current
+}
+
+// Set replaces the context of this run
+func Set(ctx *Context) {
+	current = ctx
+}
+
+// Dir returns the project directory, defaulting to the working directory
+func Dir() string {
+	if current.Dir != "" {
+		return current.Dir
+	}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	return pwd
+}
+
+// SetDir points the run at another project directory, e.g. once swan new created it
+func SetDir(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve directory %s: %v", dir, err)
+	}
+
+	current.Dir = abs
+	return nil
+}
+
+// Path joins elem onto the project root, see Root
+func Path(elem ...string) string {
+	return filepath.Join(append([]string{Root()}, elem...)...)
+}
+
+// DryRun reports whether writes should only be printed
+func DryRun() bool {
+	return current.DryRun
+}
+
+// Interactive reports whether missing args may be asked for on stdin: with
+// -i/--interactive, or when stdin is a terminal
+func Interactive() bool {
+	if current.Interactive {
+		return true
+	}
+
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// GlobalFlags are the flags ParseFlags takes, offered by shell completion
+var GlobalFlags = []string{"-C", "--dir", "--dry-run", "-v", "--verbose", "-q", "--quiet", "-i", "--interactive"}
+
+// ParseFlags removes the global flags from args and applies them to a new context.
+// global flags may appear anywhere before a --
+//
+//	-C, --dir <path>   run in the project at path instead of the working directory
+//	--dry-run          print planned writes and commands without touching disk
+//	-v, --verbose      print more detail
+//	-q, --quiet        print only errors
+//	-i, --interactive  ask for missing args on stdin, even when it is not a terminal
+func ParseFlags(args []string) (*Context, []string, error) {
+	ctx := &Context{Level: Normal}
+	rest := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		switch {
+		case arg == "-C" || arg == "--dir":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("flag %s expects a directory", arg)
+			}
+			i++
+			ctx.Dir = args[i]
+		case strings.HasPrefix(arg, "--dir="):
+			ctx.Dir = strings.TrimPrefix(arg, "--dir=")
+		case arg == "--dry-run":
+			ctx.DryRun = true
+		case arg == "-v" || arg == "--verbose":
+			ctx.Level = Verbose
+		case arg == "-q" || arg == "--quiet":
+			ctx.Level = Quiet
+		case arg == "-i" || arg == "--interactive":
+			ctx.Interactive = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+
+	if ctx.Dir != "" {
+		abs, err := filepath.Abs(ctx.Dir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve directory %s: %v", ctx.Dir, err)
+		}
+
+		info, err := os.Stat(abs)
+		if err != nil || !info.IsDir() {
+			return nil, nil, fmt.Errorf("directory not found: %s", ctx.Dir)
+		}
+		ctx.Dir = abs
+	}
+
+	return ctx, rest, nil
+}
+
+// Printf prints progress output unless --quiet is set
+func Printf(format string, a ...any) {
+	if current.Level >= Normal {
+		fmt.Printf(format, a...)
+	}
+}
+
+// Verbosef prints detail only with --verbose
+func Verbosef(format string, a ...any) {
+	if current.Level >= Verbose {
+		fmt.Printf(format, a...)
+	}
+}
+
+// Rel returns path relative to the project root for output
+func Rel(path string) string {
+	rel, err := filepath.Rel(Root(), path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}